@@ -0,0 +1,138 @@
+package xcomp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// AppError is an error meant for API clients: Status is the HTTP status,
+// Code a stable machine-readable identifier and Message safe to show.
+// Declare domain errors as AppErrors so handlers can simply return them.
+type AppError struct {
+	Status  int
+	Code    string
+	Message string
+	// Err is the underlying cause. It is logged, never sent to the client,
+	// which only sees Message; put client-facing detail there with Withf.
+	Err error
+
+	// origin is the sentinel a copy was made from, so errors.Is still matches it
+	origin *AppError
+}
+
+func NewAppError(status int, code, message string) *AppError {
+	return &AppError{Status: status, Code: code, Message: message}
+}
+
+func NewNotFound(message string) *AppError {
+	return NewAppError(fiber.StatusNotFound, "not_found", message)
+}
+
+func NewConflict(message string) *AppError {
+	return NewAppError(fiber.StatusConflict, "conflict", message)
+}
+
+// NewValidation is for requests that parse but break a business rule
+func NewValidation(message string) *AppError {
+	return NewAppError(fiber.StatusUnprocessableEntity, "validation_failed", message)
+}
+
+func NewBadRequest(message string) *AppError {
+	return NewAppError(fiber.StatusBadRequest, "bad_request", message)
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is e or the sentinel e was copied from
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t != nil && (t == e || t == e.origin)
+}
+
+// WithCause returns a copy of e carrying err as its cause
+func (e *AppError) WithCause(err error) *AppError {
+	copied := e.copy()
+	copied.Err = err
+	return copied
+}
+
+// Withf returns a copy of e with detail appended to its message, e.g.
+// ErrTooManyItems.Withf("%d items, limit is %d", n, limit)
+func (e *AppError) Withf(format string, args ...any) *AppError {
+	copied := e.copy()
+	copied.Message = e.Message + ": " + fmt.Sprintf(format, args...)
+	return copied
+}
+
+func (e *AppError) copy() *AppError {
+	copied := *e
+	if copied.origin == nil {
+		copied.origin = e
+	}
+	return &copied
+}
+
+// FiberErrorHandler answers AppErrors with their status and
+// {"error","code","message"}, fiber errors with their status, and anything
+// else with a generic 500. Server errors are logged with their operation
+// context and the client gets the request ID to quote instead; client errors
+// that carry a cause log it as a warning.
+func FiberErrorHandler(logger Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		status := fiber.StatusInternalServerError
+		body := fiber.Map{
+			"error":   "Request failed",
+			"message": "An internal error occurred",
+		}
+
+		var appErr *AppError
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &appErr):
+			status = appErr.Status
+			body = fiber.Map{
+				"error":   utils.StatusMessage(status),
+				"code":    appErr.Code,
+				"message": appErr.Message,
+			}
+			if status < fiber.StatusInternalServerError && appErr.Err != nil && logger != nil {
+				fields := append(ErrorFields(err),
+					Field("request_id", RequestIDFromContext(c.UserContext())),
+					Field("method", c.Method()),
+					Field("path", c.Path()))
+				logger.Warn("Request rejected", fields...)
+			}
+		case errors.As(err, &fiberErr):
+			status = fiberErr.Code
+			if status < fiber.StatusInternalServerError {
+				body["message"] = fiberErr.Message
+			}
+		}
+
+		if status >= fiber.StatusInternalServerError {
+			requestID := RequestIDFromContext(c.UserContext())
+			if logger != nil {
+				fields := append(ErrorFields(err),
+					Field("request_id", requestID),
+					Field("method", c.Method()),
+					Field("path", c.Path()))
+				logger.Error("Request failed", fields...)
+			}
+			body["request_id"] = requestID
+		}
+
+		return c.Status(status).JSON(body)
+	}
+}
@@ -0,0 +1,67 @@
+package xcomp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestFiberErrorHandlerHidesCauseFromClients(t *testing.T) {
+	logger, logs := newObservedLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: FiberErrorHandler(logger)})
+
+	rejected := NewValidation("order cannot be confirmed")
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		return rejected.WithCause(errors.New("stock check: pq: relation \"stock\" does not exist"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusUnprocessableEntity)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["message"] != "order cannot be confirmed" {
+		t.Fatalf("message = %q, want only the public message", body["message"])
+	}
+	if body["code"] != "validation_failed" {
+		t.Fatalf("code = %q, want validation_failed", body["code"])
+	}
+
+	entries := logs.FilterMessage("Request rejected").AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d rejections, want 1", len(entries))
+	}
+	if cause, _ := entries[0].ContextMap()["error"].(string); !strings.Contains(cause, "does not exist") {
+		t.Fatalf("logged error %q does not include the cause", cause)
+	}
+}
+
+func TestFiberErrorHandlerKeepsWithfDetail(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: FiberErrorHandler(nil)})
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		return NewValidation("too many items").Withf("%d items, limit is %d", 12, 10)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["message"] != "too many items: 12 items, limit is 10" {
+		t.Fatalf("message = %q, want the Withf detail", body["message"])
+	}
+}
@@ -0,0 +1,57 @@
+package xcomp
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// GetSet is the cache side of CacheAside. Get reports a miss with a zero
+// value; Set derives the key from the value, as the example cache
+// repositories do.
+type GetSet[K, T any] interface {
+	Get(ctx context.Context, key K) (T, error)
+	Set(ctx context.Context, value T, ttl time.Duration) error
+}
+
+// CacheAside returns the cached value for key, or calls loader and caches
+// its result for ttl. A failing cache read counts as a miss and a failing
+// write is only logged, so the cache never fails the call; loader errors are
+// returned as is. A zero result, such as a nil pointer, is returned without
+// being cached, since Get could not tell it from a miss. Failures are logged
+// through LoggerFromContext.
+func CacheAside[K, T any](ctx context.Context, cache GetSet[K, T], key K, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	logger := LoggerFromContext(ctx)
+
+	value, err := cache.Get(ctx, key)
+	if err == nil && !isZeroValue(value) {
+		return value, nil
+	}
+	if err != nil {
+		logger.Debug("Cache read failed, loading from source",
+			Field("key", key),
+			Err(err))
+	}
+
+	value, err = loader(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	if isZeroValue(value) {
+		return value, nil
+	}
+
+	if setErr := cache.Set(ctx, value, ttl); setErr != nil {
+		logger.Warn("Failed to populate cache",
+			Field("key", key),
+			Err(setErr))
+	}
+	return value, nil
+}
+
+func isZeroValue[T any](value T) bool {
+	v := reflect.ValueOf(&value).Elem()
+	return v.IsZero()
+}
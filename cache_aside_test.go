@@ -0,0 +1,93 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type cachedItem struct {
+	ID   string
+	Name string
+}
+
+// mapCache is a GetSet keyed by item ID that records its Set calls
+type mapCache struct {
+	items  map[string]*cachedItem
+	sets   int
+	getErr error
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{items: make(map[string]*cachedItem)}
+}
+
+func (c *mapCache) Get(ctx context.Context, key string) (*cachedItem, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	return c.items[key], nil
+}
+
+func (c *mapCache) Set(ctx context.Context, value *cachedItem, ttl time.Duration) error {
+	c.sets++
+	c.items[value.ID] = value
+	return nil
+}
+
+func TestCacheAsideLoadsOnMissAndCaches(t *testing.T) {
+	cache := newMapCache()
+	loads := 0
+	loader := func(ctx context.Context) (*cachedItem, error) {
+		loads++
+		return &cachedItem{ID: "a", Name: "loaded"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		item, err := CacheAside(context.Background(), GetSet[string, *cachedItem](cache), "a", time.Minute, loader)
+		if err != nil {
+			t.Fatalf("CacheAside: %v", err)
+		}
+		if item.Name != "loaded" {
+			t.Fatalf("CacheAside returned %+v", item)
+		}
+	}
+	if loads != 1 || cache.sets != 1 {
+		t.Fatalf("loader ran %d times and Set %d times, want 1 and 1", loads, cache.sets)
+	}
+}
+
+func TestCacheAsideDoesNotCacheZeroValue(t *testing.T) {
+	cache := newMapCache()
+
+	item, err := CacheAside(context.Background(), GetSet[string, *cachedItem](cache), "missing", time.Minute,
+		func(ctx context.Context) (*cachedItem, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("CacheAside: %v", err)
+	}
+	if item != nil {
+		t.Fatalf("CacheAside returned %+v, want nil", item)
+	}
+	if cache.sets != 0 {
+		t.Fatalf("Set called %d times for a nil result", cache.sets)
+	}
+}
+
+func TestCacheAsideReturnsLoaderErrorAndToleratesCacheErrors(t *testing.T) {
+	cache := newMapCache()
+	cache.getErr = errors.New("redis down")
+	errLoad := errors.New("not found")
+
+	_, err := CacheAside(context.Background(), GetSet[string, *cachedItem](cache), "a", time.Minute,
+		func(ctx context.Context) (*cachedItem, error) { return nil, errLoad })
+	if !errors.Is(err, errLoad) {
+		t.Fatalf("CacheAside returned %v, want the loader error", err)
+	}
+
+	item, err := CacheAside(context.Background(), GetSet[string, *cachedItem](cache), "a", time.Minute,
+		func(ctx context.Context) (*cachedItem, error) { return &cachedItem{ID: "a"}, nil })
+	if err != nil || item == nil {
+		t.Fatalf("CacheAside with a failing cache read = %v, %v; want the loaded item", item, err)
+	}
+}
@@ -0,0 +1,72 @@
+package xcomp
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+type CompressionLevel int
+
+const (
+	CompressionLevelDefault CompressionLevel = iota
+	CompressionLevelBestSpeed
+	CompressionLevelBestCompression
+)
+
+type CompressionConfig struct {
+	Level CompressionLevel
+	// MinSize skips bodies smaller than this many bytes, where compression costs more than it saves
+	MinSize int
+	// ContentTypes are prefixes of compressible content types, e.g. "application/json", "text/"
+	ContentTypes []string
+}
+
+func (cfg CompressionConfig) withDefaults() CompressionConfig {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = 1024
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = []string{fiber.MIMEApplicationJSON, "text/"}
+	}
+	return cfg
+}
+
+// NewCompressionMiddleware brotli- or gzip-compresses responses according to the
+// client's Accept-Encoding, skipping small bodies and content types not listed
+func NewCompressionMiddleware(cfg CompressionConfig) fiber.Handler {
+	cfg = cfg.withDefaults()
+
+	brotliLevel, gzipLevel := fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression
+	switch cfg.Level {
+	case CompressionLevelBestSpeed:
+		brotliLevel, gzipLevel = fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed
+	case CompressionLevelBestCompression:
+		brotliLevel, gzipLevel = fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression
+	}
+	compress := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {}, brotliLevel, gzipLevel)
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		response := c.Response()
+		if len(response.Body()) < cfg.MinSize || !cfg.compressible(string(response.Header.ContentType())) {
+			return nil
+		}
+
+		compress(c.Context())
+		return nil
+	}
+}
+
+func (cfg CompressionConfig) compressible(contentType string) bool {
+	for _, prefix := range cfg.ContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
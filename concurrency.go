@@ -0,0 +1,69 @@
+package xcomp
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEachN calls fn for every item with at most concurrency calls in flight and
+// returns the results in item order. The first error cancels the context passed to
+// the remaining calls, stops starting new ones, and is returned once in-flight calls
+// finish. A concurrency below 1 runs the items one at a time.
+//
+// Use it for fan-out over a small, bounded set, such as loading related records for
+// one page of results when no batch query exists. Prefer a single batch query
+// (WHERE id = ANY($1)) when there is one: it costs one round trip and one pool
+// connection, where ForEachN holds up to concurrency connections at once.
+func ForEachN[T, R any](ctx context.Context, items []T, concurrency int, fn func(context.Context, T) (R, error)) ([]R, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	semaphore := make(chan struct{}, concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i, item := range items {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			fail(ctx.Err())
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, err := fn(ctx, item)
+			if err != nil {
+				fail(err)
+				return
+			}
+			results[i] = result
+		}(i, item)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
@@ -0,0 +1,99 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachNReturnsResultsInOrder(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var inFlight, maxInFlight atomic.Int32
+
+	results, err := ForEachN(context.Background(), items, 3, func(ctx context.Context, n int) (int, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		return n * n, nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachN returned %v", err)
+	}
+	for i, n := range items {
+		if results[i] != n*n {
+			t.Fatalf("results[%d] = %d, want %d", i, results[i], n*n)
+		}
+	}
+	if got := maxInFlight.Load(); got > 3 {
+		t.Fatalf("%d calls in flight, want at most 3", got)
+	}
+}
+
+func TestForEachNStopsStartingCallsAfterError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var calls atomic.Int32
+
+	results, err := ForEachN(context.Background(), []int{0, 1, 2, 3, 4, 5, 6, 7}, 1, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		if n == 3 {
+			return 0, errBoom
+		}
+		return n, nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("ForEachN returned %v, want %v", err, errBoom)
+	}
+	if results != nil {
+		t.Fatalf("ForEachN returned results %v alongside an error", results)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("fn called %d times, want 4", got)
+	}
+}
+
+func TestForEachNCancelsInFlightCallsOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	started := make(chan struct{})
+	var sawCancel atomic.Bool
+
+	_, err := ForEachN(context.Background(), []int{0, 1}, 2, func(ctx context.Context, n int) (int, error) {
+		if n == 0 {
+			close(started)
+			<-ctx.Done()
+			sawCancel.Store(true)
+			return 0, ctx.Err()
+		}
+		<-started
+		return 0, errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("ForEachN returned %v, want the first error %v", err, errBoom)
+	}
+	if !sawCancel.Load() {
+		t.Fatal("in-flight call did not see its context cancelled")
+	}
+}
+
+func TestForEachNStopsWhenParentContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+
+	_, err := ForEachN(ctx, []int{0, 1, 2, 3}, 1, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		cancel()
+		return n, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ForEachN returned %v, want %v", err, context.Canceled)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("fn called %d times after cancellation, want 1", got)
+	}
+}
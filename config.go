@@ -6,43 +6,67 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
 type ConfigService struct {
-	config      map[string]any
-	envMap      map[string]string
-	mu          sync.RWMutex
-	viper       *viper.Viper
-	envPrefix   string
-	initialized bool
+	config       map[string]any
+	envMap       map[string]string
+	mu           sync.RWMutex
+	viper        *viper.Viper
+	envPrefix    string
+	envSeparator string
+	initialized  bool
+	paths        []string
+	onReload     []func(changedKeys []string)
+	onChange     []func(cs *ConfigService)
+	defaults     map[string]any
+	overrides    map[string]any
+	watcher      *fsnotify.Watcher
 }
 
 // ConfigOptions for advanced configuration
 type ConfigOptions struct {
 	EnvPrefix    string
 	EnvSeparator string
-	AutoReload   bool
+	// AutoReload reloads the config whenever one of its files changes; a file that
+	// fails to parse keeps the previous config. Close stops watching.
+	AutoReload bool
+	// OnReloadError receives watcher and automatic reload failures
+	OnReloadError func(err error)
 }
 
 func NewConfigService(configPaths ...string) *ConfigService {
-	opts := ConfigOptions{
-		EnvPrefix:    "",
-		EnvSeparator: "__",
-		AutoReload:   false,
+	return NewConfigServiceWithOptions(ConfigOptions{}, configPaths...)
+}
+
+// NewConfigServiceWithOptions is NewConfigService with an env prefix, separator
+// ("__" by default) or automatic reloading
+func NewConfigServiceWithOptions(opts ConfigOptions, configPaths ...string) *ConfigService {
+	if opts.EnvSeparator == "" {
+		opts.EnvSeparator = "__"
 	}
 
 	cs := &ConfigService{
-		config:    make(map[string]any),
-		envMap:    make(map[string]string),
-		viper:     viper.New(),
-		envPrefix: opts.EnvPrefix,
+		config:       make(map[string]any),
+		defaults:     make(map[string]any),
+		overrides:    make(map[string]any),
+		envMap:       make(map[string]string),
+		viper:        viper.New(),
+		envPrefix:    opts.EnvPrefix,
+		envSeparator: opts.EnvSeparator,
+		paths:        configPaths,
 	}
 
 	// Load .env file
@@ -57,6 +81,12 @@ func NewConfigService(configPaths ...string) *ConfigService {
 	}
 
 	cs.initialized = true
+
+	if opts.AutoReload {
+		if err := cs.watch(opts.OnReloadError); err != nil && opts.OnReloadError != nil {
+			opts.OnReloadError(err)
+		}
+	}
 	return cs
 }
 
@@ -78,13 +108,24 @@ func (cs *ConfigService) loadEnvironmentVariables(opts ConfigOptions) {
 }
 
 func (cs *ConfigService) loadConfigFile(path string) error {
+	fileConfig, err := readConfigFile(path)
+	if err != nil || fileConfig == nil {
+		return err
+	}
+
+	cs.mergeConfig(fileConfig)
+	return nil
+}
+
+// readConfigFile parses one config file; a missing file yields a nil map
+func readConfigFile(path string) (map[string]any, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil
+		return nil, nil
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
 	var fileConfig map[string]any
@@ -93,27 +134,175 @@ func (cs *ConfigService) loadConfigFile(path string) error {
 	switch ext {
 	case ".yaml", ".yml":
 		if err := yaml.Unmarshal(data, &fileConfig); err != nil {
-			return fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fileConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config %s: %w", path, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &fileConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML config %s: %w", path, err)
 		}
 	default:
-		return fmt.Errorf("unsupported config file format: %s (only .yaml/.yml supported)", ext)
+		return nil, fmt.Errorf("unsupported config file format: %s (only .yaml/.yml/.json/.toml supported)", ext)
 	}
 
-	cs.mergeConfig(fileConfig)
+	if fileConfig == nil {
+		fileConfig = make(map[string]any)
+	}
+	normalizeConfigNumbers(fileConfig)
+	return fileConfig, nil
+}
 
-	// Also load into viper for advanced env override support
-	configBuffer, _ := json.Marshal(fileConfig)
-	cs.viper.ReadConfig(bytes.NewBuffer(configBuffer))
+// normalizeConfigNumbers converts JSON numbers and TOML int64s to the int and
+// float64 values YAML produces, so accessors behave the same for every format
+func normalizeConfigNumbers(tree map[string]any) {
+	for key, value := range tree {
+		tree[key] = normalizeConfigNumber(value)
+	}
+}
+
+func normalizeConfigNumber(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case int64:
+		return int(v)
+	case map[string]any:
+		normalizeConfigNumbers(v)
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeConfigNumber(item)
+		}
+	}
+	return value
+}
+
+// OnReload registers fn to run after a Reload that changed at least one key. It
+// receives the changed dotted keys, sorted.
+func (cs *ConfigService) OnReload(fn func(changedKeys []string)) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.onReload = append(cs.onReload, fn)
+}
+
+// Reload re-reads the config files and swaps the new values in. If any file fails
+// to parse the current config is kept and the error returned. Values from Set and
+// SetDefault are kept; environment variables are not re-read.
+func (cs *ConfigService) Reload() error {
+	var files []map[string]any
+	for _, path := range cs.paths {
+		fileConfig, err := readConfigFile(path)
+		if err != nil {
+			return err
+		}
+		if fileConfig != nil {
+			files = append(files, fileConfig)
+		}
+	}
 
+	cs.mu.Lock()
+	next := make(map[string]any)
+	deepMerge(next, cs.defaults)
+	for _, fileConfig := range files {
+		deepMerge(next, fileConfig)
+	}
+	mergeOverrides(next, cs.overrides)
+
+	changedKeys := changedConfigKeys(cs.config, next)
+	cs.config = next
+	cs.syncViperLocked()
+	handlers := append([]func([]string){}, cs.onReload...)
+	changeHandlers := append([]func(*ConfigService){}, cs.onChange...)
+	cs.mu.Unlock()
+
+	if len(changedKeys) > 0 {
+		for _, handler := range handlers {
+			handler(changedKeys)
+		}
+		for _, handler := range changeHandlers {
+			handler(cs)
+		}
+	}
 	return nil
 }
 
+// changedConfigKeys lists the dotted leaf keys added, removed or modified between
+// two config trees
+func changedConfigKeys(previous, next map[string]any) []string {
+	before := make(map[string]any)
+	flattenConfig("", previous, before)
+	after := make(map[string]any)
+	flattenConfig("", next, after)
+
+	var changed []string
+	for key, value := range after {
+		if old, exists := before[key]; !exists || !reflect.DeepEqual(old, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+func flattenConfig(prefix string, tree map[string]any, leaves map[string]any) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			flattenConfig(key, nested, leaves)
+			continue
+		}
+		leaves[key] = value
+	}
+}
+
+// mergeConfig deep merges a later file over the loaded config, so it only
+// overrides the leaf keys it sets
 func (cs *ConfigService) mergeConfig(newConfig map[string]any) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
-	for key, value := range newConfig {
-		cs.config[key] = value
+	deepMerge(cs.config, newConfig)
+	cs.syncViperLocked()
+}
+
+// syncViperLocked loads the merged config into viper for env override support.
+// ReadConfig replaces what viper holds, so it is always given the whole tree.
+func (cs *ConfigService) syncViperLocked() {
+	configBuffer, _ := json.Marshal(cs.config)
+	cs.viper.ReadConfig(bytes.NewBuffer(configBuffer))
+}
+
+// deepMerge copies src into dst, recursing where both hold a section; any other
+// value in src, including a list, replaces the one in dst. Sections are copied, so
+// dst never shares a map with src.
+func deepMerge(dst, src map[string]any) {
+	for key, value := range src {
+		if srcSection, ok := value.(map[string]any); ok {
+			merged := make(map[string]any)
+			if dstSection, ok := dst[key].(map[string]any); ok {
+				deepMerge(merged, dstSection)
+			}
+			deepMerge(merged, srcSection)
+			dst[key] = merged
+			continue
+		}
+		dst[key] = value
 	}
 }
 
@@ -121,6 +310,14 @@ func (cs *ConfigService) Get(key string) any {
 	cs.mu.RLock()
 	defer cs.mu.RUnlock()
 
+	// A flat "a.b" key wins over a nested a: {b: ...}, which viper would
+	// resolve instead; an environment override still wins over both
+	if strings.Contains(key, ".") {
+		if value, exists := cs.config[key]; exists && !cs.envOverridden(key) {
+			return value
+		}
+	}
+
 	// Try viper first (supports env overrides with prefixes)
 	if cs.initialized && cs.viper.IsSet(key) {
 		return cs.viper.Get(key)
@@ -138,6 +335,18 @@ func (cs *ConfigService) Get(key string) any {
 	return cs.getNestedValue(key)
 }
 
+// envOverridden reports whether an environment variable supplies key, using
+// the same name viper derives: prefix, "_", then the key with "." replaced by
+// the separator, upper-cased
+func (cs *ConfigService) envOverridden(key string) bool {
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", cs.envSeparator))
+	if cs.envPrefix != "" {
+		name = strings.ToUpper(cs.envPrefix) + "_" + name
+	}
+	_, exists := os.LookupEnv(name)
+	return exists
+}
+
 func (cs *ConfigService) GetString(key string, defaultValue ...string) string {
 	value := cs.Get(key)
 	if value == nil {
@@ -203,25 +412,141 @@ func (cs *ConfigService) GetBool(key string, defaultValue ...bool) bool {
 	return false
 }
 
+// GetDuration reads a duration such as "30s" or "1500ms"; a bare number, from YAML
+// or the environment, is seconds. Missing or unparsable values give the default.
+func (cs *ConfigService) GetDuration(key string, defaultValue ...time.Duration) time.Duration {
+	if converted, err := cs.convert(key, durationType); err == nil {
+		return converted.Interface().(time.Duration)
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// GetFloat64 reads an int, float or numeric string; missing or unparsable values
+// give the default
+func (cs *ConfigService) GetFloat64(key string, defaultValue ...float64) float64 {
+	if converted, err := cs.convert(key, reflect.TypeOf(float64(0))); err == nil {
+		return converted.Float()
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// GetStringSlice reads a YAML list or a comma-separated string such as an env
+// override ("a, b"), trimming each entry. Missing keys give the default.
+func (cs *ConfigService) GetStringSlice(key string, defaultValue ...[]string) []string {
+	var result []string
+	switch v := cs.Get(key).(type) {
+	case []any:
+		for _, item := range v {
+			result = append(result, fmt.Sprintf("%v", item))
+		}
+		return result
+	case []string:
+		return append(result, v...)
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+		return result
+	case nil:
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return nil
+}
+
+// GetIntSlice is GetStringSlice for integers; a missing key or any non-numeric
+// entry gives the default
+func (cs *ConfigService) GetIntSlice(key string, defaultValue ...[]int) []int {
+	if cs.Get(key) != nil {
+		if items, ok := parseIntSlice(cs.GetStringSlice(key)); ok {
+			return items
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return nil
+}
+
+func parseIntSlice(items []string) ([]int, bool) {
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		i, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, i)
+	}
+	return result, true
+}
+
+func (cs *ConfigService) convert(key string, target reflect.Type) (reflect.Value, error) {
+	value := cs.Get(key)
+	if value == nil {
+		return reflect.Value{}, fmt.Errorf("config key '%s' is not set", key)
+	}
+	return convertConfigValue(value, target)
+}
+
+// getNestedValue resolves a dotted key against the loaded config.
+//
+// An exact top-level key wins over the nested path, so for "a.b" a flat
+// {"a.b": 1} is returned before {"a": {"b": 2}}. Empty keys and keys with
+// empty segments ("a.", ".a", "a..b") resolve to nil, as does a path that
+// runs through a non-map value.
 func (cs *ConfigService) getNestedValue(key string) any {
+	if key == "" {
+		return nil
+	}
+
+	if value, exists := cs.config[key]; exists {
+		return value
+	}
+
 	keys := strings.Split(key, ".")
 	current := cs.config
 
 	for i, k := range keys {
+		if k == "" {
+			return nil
+		}
+
 		if i == len(keys)-1 {
 			return current[k]
 		}
 
-		if next, ok := current[k].(map[string]any); ok {
-			current = next
-		} else {
+		next, ok := current[k].(map[string]any)
+		if !ok {
 			return nil
 		}
+		current = next
 	}
 
 	return nil
 }
 
+// GetAllRedacted is GetAll with secrets masked, for config dumps and debug endpoints
+func (cs *ConfigService) GetAllRedacted() map[string]any {
+	all := cs.GetAll()
+	for key, value := range all {
+		all[key] = Redact(key, value)
+	}
+	return all
+}
+
+// GetAll returns the raw values, secrets included; use GetAllRedacted to display them
 func (cs *ConfigService) GetAll() map[string]any {
 	cs.mu.RLock()
 	defer cs.mu.RUnlock()
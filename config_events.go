@@ -0,0 +1,85 @@
+package xcomp
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// ConfigChangedTopic is the EventBus topic of ConfigChanged events
+const ConfigChangedTopic = "config.changed"
+
+// ConfigChanged is published after a config reload that changed at least one key.
+// Services that depend on reloadable settings (rate limits, log sampling, feature
+// flags, cache TTLs) subscribe to it instead of watching the config files themselves.
+// On a distributed bus other instances receive it too, so handlers should re-read
+// the values from their own ConfigService rather than assume they changed locally.
+type ConfigChanged struct {
+	Keys []string `json:"keys"`
+}
+
+// Affects reports whether key, or any key below it, changed: Affects("cache")
+// matches "cache.ttl_seconds"
+func (e ConfigChanged) Affects(key string) bool {
+	for _, changed := range e.Keys {
+		if changed == key || strings.HasPrefix(changed, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// PublishConfigChanges publishes a ConfigChanged event on bus after every reload of
+// config that changed something
+func PublishConfigChanges(config *ConfigService, bus EventBus, logger Logger) {
+	config.OnReload(func(changedKeys []string) {
+		err := bus.Publish(context.Background(), ConfigChangedTopic, ConfigChanged{Keys: changedKeys})
+		if err != nil && logger != nil {
+			logger.Error("Failed to publish config change", Err(err))
+		}
+	})
+}
+
+// OnConfigChanged subscribes fn to ConfigChanged events on bus
+func OnConfigChanged(bus EventBus, fn func(ctx context.Context, change ConfigChanged) error) {
+	bus.Subscribe(ConfigChangedTopic, func(ctx context.Context, event Event) error {
+		var change ConfigChanged
+		if err := event.Decode(&change); err != nil {
+			return err
+		}
+		return fn(ctx, change)
+	})
+}
+
+// ReloadOnSignal reloads the config files every time one of signals arrives (SIGHUP
+// by default) until ctx is done. A failed reload keeps the previous config.
+func (cs *ConfigService) ReloadOnSignal(ctx context.Context, logger Logger, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, signals...)
+
+	go func() {
+		defer signal.Stop(reload)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				if err := cs.Reload(); err != nil {
+					if logger != nil {
+						logger.Error("Config reload failed, keeping previous config", Err(err))
+					}
+					continue
+				}
+				if logger != nil {
+					logger.Info("Config reloaded")
+				}
+			}
+		}
+	}()
+}
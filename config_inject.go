@@ -0,0 +1,146 @@
+package xcomp
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// injectConfig sets a field tagged `config:"server.port"` from the registered
+// ConfigService. A missing key leaves the field as it is, so a value set before
+// Inject acts as the default; a value that can't be converted is an error.
+func (c *Container) injectConfig(field reflect.Value, fieldName, key string) error {
+	config, ok := c.Get("ConfigService").(*ConfigService)
+	if !ok {
+		return fmt.Errorf("field '%s' has config tag '%s' but no ConfigService is registered", fieldName, key)
+	}
+
+	value := config.Get(key)
+	if value == nil {
+		return nil
+	}
+
+	converted, err := convertConfigValue(value, field.Type())
+	if err != nil {
+		return fmt.Errorf("config '%s' for field '%s': %w", key, fieldName, err)
+	}
+	field.Set(converted)
+	return nil
+}
+
+// convertConfigValue converts a YAML or environment value to target. Environment
+// overrides arrive as strings, so strings are parsed for every scalar kind.
+func convertConfigValue(value any, target reflect.Type) (reflect.Value, error) {
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(target) && target != durationType {
+		return source, nil
+	}
+
+	mismatch := fmt.Errorf("cannot convert %T value %v to %s", value, value, target)
+
+	if target == durationType {
+		switch v := value.(type) {
+		case string:
+			v = strings.TrimSpace(v)
+			if d, err := time.ParseDuration(v); err == nil {
+				return reflect.ValueOf(d), nil
+			}
+			if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+				return reflect.ValueOf(time.Duration(seconds * float64(time.Second))), nil
+			}
+		case int:
+			// Bare numbers are seconds
+			return reflect.ValueOf(time.Duration(v) * time.Second), nil
+		case float64:
+			return reflect.ValueOf(time.Duration(v * float64(time.Second))), nil
+		}
+		return reflect.Value{}, mismatch
+	}
+
+	result := reflect.New(target).Elem()
+	switch target.Kind() {
+	case reflect.String:
+		result.SetString(fmt.Sprintf("%v", value))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch v := value.(type) {
+		case int:
+			n = int64(v)
+		case float64:
+			if v != float64(int64(v)) {
+				return reflect.Value{}, mismatch
+			}
+			n = int64(v)
+		case string:
+			parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return reflect.Value{}, mismatch
+			}
+			n = parsed
+		default:
+			return reflect.Value{}, mismatch
+		}
+		if result.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("value %d overflows %s", n, target)
+		}
+		result.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch v := value.(type) {
+		case int:
+			f = float64(v)
+		case float64:
+			f = v
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return reflect.Value{}, mismatch
+			}
+			f = parsed
+		default:
+			return reflect.Value{}, mismatch
+		}
+		result.SetFloat(f)
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			result.SetBool(v)
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return reflect.Value{}, mismatch
+			}
+			result.SetBool(parsed)
+		default:
+			return reflect.Value{}, mismatch
+		}
+	case reflect.Slice:
+		// YAML lists, or comma-separated strings from the environment
+		var items []any
+		switch v := value.(type) {
+		case []any:
+			items = v
+		case string:
+			for _, item := range strings.Split(v, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		default:
+			return reflect.Value{}, mismatch
+		}
+		result = reflect.MakeSlice(target, len(items), len(items))
+		for i, item := range items {
+			converted, err := convertConfigValue(item, target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			result.Index(i).Set(converted)
+		}
+	default:
+		return reflect.Value{}, mismatch
+	}
+	return result, nil
+}
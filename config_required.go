@@ -0,0 +1,89 @@
+package xcomp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrConfigKeyMissing = errors.New("required config key is missing")
+
+// RequireString returns the value for key, or an error when it is absent or empty
+func (cs *ConfigService) RequireString(key string) (string, error) {
+	value := cs.Get(key)
+	if isMissingConfigValue(value) {
+		return "", fmt.Errorf("%w: %s", ErrConfigKeyMissing, key)
+	}
+
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// RequireInt returns the value for key, or an error when it is absent or not an integer
+func (cs *ConfigService) RequireInt(key string) (int, error) {
+	value := cs.Get(key)
+	if isMissingConfigValue(value) {
+		return 0, fmt.Errorf("%w: %s", ErrConfigKeyMissing, key)
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("config key %s is not an integer: %v", key, value)
+}
+
+// MustGetString is RequireString for settings the app cannot start without; it panics on error
+func (cs *ConfigService) MustGetString(key string) string {
+	value, err := cs.RequireString(key)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// MustGetInt is RequireInt for settings the app cannot start without; it panics on error
+func (cs *ConfigService) MustGetInt(key string) int {
+	value, err := cs.RequireInt(key)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// RequireKeys checks every key at once and reports all missing ones in a single error,
+// so startup fails with the full list instead of one key per restart
+func (cs *ConfigService) RequireKeys(keys []string) error {
+	var missing []string
+	for _, key := range keys {
+		if isMissingConfigValue(cs.Get(key)) {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrConfigKeyMissing, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func isMissingConfigValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	str, ok := value.(string)
+	return ok && strings.TrimSpace(str) == ""
+}
@@ -0,0 +1,46 @@
+package xcomp
+
+import "strings"
+
+// Set overrides key in code, creating the sections of a dotted key as needed.
+// Environment variables still take precedence, and the value survives Reload.
+func (cs *ConfigService) Set(key string, value any) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	setNested(cs.overrides, strings.Split(key, "."), value)
+	setConfigValue(cs.config, key, value)
+	cs.syncViperLocked()
+}
+
+// SetDefault gives key a value for when neither the config files nor Set provide
+// one. A later Reload that drops the key from the files falls back to it again.
+func (cs *ConfigService) SetDefault(key string, value any) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	setNested(cs.defaults, strings.Split(key, "."), value)
+	if cs.getNestedValue(key) == nil {
+		setConfigValue(cs.config, key, value)
+		cs.syncViperLocked()
+	}
+}
+
+// setConfigValue writes key into the nested sections and, when tree already has
+// a flat "a.b" entry for it, into that too, since Get reads the flat entry first
+func setConfigValue(tree map[string]any, key string, value any) {
+	setNested(tree, strings.Split(key, "."), value)
+	if _, exists := tree[key]; exists && strings.Contains(key, ".") {
+		tree[key] = value
+	}
+}
+
+// mergeOverrides merges the values from Set over tree, including over flat keys
+// a config file spells out
+func mergeOverrides(tree, overrides map[string]any) {
+	leaves := make(map[string]any)
+	flattenConfig("", overrides, leaves)
+	for key, value := range leaves {
+		setConfigValue(tree, key, value)
+	}
+}
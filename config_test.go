@@ -0,0 +1,297 @@
+package xcomp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	return writeNamedConfigFile(t, "config.yaml", content)
+}
+
+func writeNamedConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const flatAndNestedConfig = `
+"cache.ttl": flat
+cache:
+  ttl: nested
+  size: 10
+`
+
+func TestGetFlatKeyWinsOverNested(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, flatAndNestedConfig))
+
+	if got := cs.Get("cache.ttl"); got != "flat" {
+		t.Fatalf(`Get("cache.ttl") = %v, want the flat key's "flat"`, got)
+	}
+	if got := cs.GetInt("cache.size"); got != 10 {
+		t.Fatalf(`GetInt("cache.size") = %d, want the nested 10`, got)
+	}
+}
+
+func TestGetEnvOverridesFlatKey(t *testing.T) {
+	t.Setenv("CACHE__TTL", "from-env")
+	cs := NewConfigService(writeConfigFile(t, flatAndNestedConfig))
+
+	if got := cs.GetString("cache.ttl"); got != "from-env" {
+		t.Fatalf(`GetString("cache.ttl") = %q, want the environment override`, got)
+	}
+}
+
+func TestSetUpdatesFlatKey(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, flatAndNestedConfig))
+
+	cs.Set("cache.ttl", "from-set")
+	if got := cs.Get("cache.ttl"); got != "from-set" {
+		t.Fatalf(`Get("cache.ttl") after Set = %v, want "from-set"`, got)
+	}
+	if err := cs.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cs.Get("cache.ttl"); got != "from-set" {
+		t.Fatalf(`Get("cache.ttl") after Reload = %v, want the Set value to survive`, got)
+	}
+}
+
+func TestGetEnvOverridesSet(t *testing.T) {
+	t.Setenv("CACHE__SIZE", "50")
+	cs := NewConfigService(writeConfigFile(t, flatAndNestedConfig))
+
+	cs.Set("cache.size", 20)
+	if got := cs.GetInt("cache.size"); got != 50 {
+		t.Fatalf(`GetInt("cache.size") = %d, want the environment's 50 over Set`, got)
+	}
+}
+
+func TestSetDefaultKeepsExistingValues(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, flatAndNestedConfig))
+
+	cs.SetDefault("cache.size", 99)
+	if got := cs.GetInt("cache.size"); got != 10 {
+		t.Fatalf(`GetInt("cache.size") = %d, want the file's 10`, got)
+	}
+
+	cs.Set("cache.ttl", "from-set")
+	cs.SetDefault("cache.ttl", "default")
+	if got := cs.Get("cache.ttl"); got != "from-set" {
+		t.Fatalf(`Get("cache.ttl") = %v, want the Set value`, got)
+	}
+
+	cs.SetDefault("cache.shards", 4)
+	if got := cs.GetInt("cache.shards"); got != 4 {
+		t.Fatalf(`GetInt("cache.shards") = %d, want the default 4 for a missing key`, got)
+	}
+}
+
+func TestLaterConfigFileMergesNestedKeys(t *testing.T) {
+	base := writeConfigFile(t, "database:\n  host: db.internal\n  port: 5432\n")
+	override := writeConfigFile(t, "database:\n  host: localhost\n")
+	cs := NewConfigService(base, override)
+
+	if got := cs.GetString("database.host"); got != "localhost" {
+		t.Fatalf(`GetString("database.host") = %q, want the override's "localhost"`, got)
+	}
+	if got := cs.GetInt("database.port"); got != 5432 {
+		t.Fatalf(`GetInt("database.port") = %d, want 5432 kept from the base file`, got)
+	}
+}
+
+func TestGetStringSlice(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, `
+cors:
+  origins:
+    - https://a.example
+    - https://b.example
+kafka:
+  brokers: "broker-1:9092, broker-2:9092 ,"
+`))
+	fallback := []string{"http://localhost:3000"}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{key: "cors.origins", want: []string{"https://a.example", "https://b.example"}},
+		{key: "kafka.brokers", want: []string{"broker-1:9092", "broker-2:9092"}},
+		{key: "cors.missing", want: fallback},
+	}
+	for _, tt := range tests {
+		if got := cs.GetStringSlice(tt.key, fallback); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetStringSlice(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFileFormatsLoadAlike(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+server:
+  port: 8080
+  debug: true
+  ratio: 0.5
+  hosts: [a, b]
+`,
+		"config.json": `{"server": {"port": 8080, "debug": true, "ratio": 0.5, "hosts": ["a", "b"]}}`,
+		"config.toml": `
+[server]
+port = 8080
+debug = true
+ratio = 0.5
+hosts = ["a", "b"]
+`,
+	}
+
+	want := NewConfigService(writeNamedConfigFile(t, "config.yaml", files["config.yaml"]))
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cs := NewConfigService(writeNamedConfigFile(t, name, content))
+
+			if got := cs.GetInt("server.port"); got != 8080 {
+				t.Errorf(`GetInt("server.port") = %d, want 8080`, got)
+			}
+			if !cs.GetBool("server.debug") {
+				t.Error(`GetBool("server.debug") = false, want true`)
+			}
+			if got := cs.GetFloat64("server.ratio"); got != 0.5 {
+				t.Errorf(`GetFloat64("server.ratio") = %v, want 0.5`, got)
+			}
+			if got := cs.GetStringSlice("server.hosts"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+				t.Errorf(`GetStringSlice("server.hosts") = %q, want [a b]`, got)
+			}
+			if got := cs.Get("server"); !reflect.DeepEqual(got, want.Get("server")) {
+				t.Errorf(`Get("server") = %#v, want the YAML file's %#v`, got, want.Get("server"))
+			}
+		})
+	}
+}
+
+func TestReadConfigFileRejectsUnknownFormat(t *testing.T) {
+	if _, err := readConfigFile(writeNamedConfigFile(t, "config.ini", "port = 8080\n")); err == nil {
+		t.Fatal("readConfigFile accepted an .ini file")
+	}
+}
+
+const numericConfig = `
+timeouts:
+  read: 30s
+  write: 1500ms
+  idle: 90
+  broken: soon
+rates:
+  int: 3
+  float: 0.25
+  string: "1.5"
+  broken: fast
+`
+
+func TestGetDuration(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, numericConfig))
+	const fallback = 5 * time.Second
+
+	tests := []struct {
+		key  string
+		want time.Duration
+	}{
+		{key: "timeouts.read", want: 30 * time.Second},
+		{key: "timeouts.write", want: 1500 * time.Millisecond},
+		{key: "timeouts.idle", want: 90 * time.Second},
+		{key: "timeouts.broken", want: fallback},
+		{key: "timeouts.missing", want: fallback},
+	}
+	for _, tt := range tests {
+		if got := cs.GetDuration(tt.key, fallback); got != tt.want {
+			t.Errorf("GetDuration(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestGetFloat64(t *testing.T) {
+	cs := NewConfigService(writeConfigFile(t, numericConfig))
+	const fallback = 9.5
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{key: "rates.int", want: 3},
+		{key: "rates.float", want: 0.25},
+		{key: "rates.string", want: 1.5},
+		{key: "rates.broken", want: fallback},
+		{key: "rates.missing", want: fallback},
+	}
+	for _, tt := range tests {
+		if got := cs.GetFloat64(tt.key, fallback); got != tt.want {
+			t.Errorf("GetFloat64(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func FuzzGetNestedValue(f *testing.F) {
+	for _, seed := range []string{"", ".", "a", "a.b", "a.b.c", "a..b", ".a", "a.", "a.b.c.d", "s.x", "flat.key"} {
+		f.Add(seed)
+	}
+
+	cs := &ConfigService{config: map[string]any{
+		"a": map[string]any{
+			"b": map[string]any{"c": "deep"},
+			"n": 1,
+		},
+		"a.b":      "flat",
+		"flat.key": true,
+		"s":        "scalar",
+	}}
+
+	f.Fuzz(func(t *testing.T, key string) {
+		got := cs.getNestedValue(key)
+
+		if flat, exists := cs.config[key]; exists && key != "" {
+			if got == nil || !sameValue(got, flat) {
+				t.Fatalf("getNestedValue(%q) = %v, want the exact key's %v", key, got, flat)
+			}
+			return
+		}
+
+		if key == "" || strings.Contains(key, "..") || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") {
+			if got != nil {
+				t.Fatalf("getNestedValue(%q) = %v, want nil for an empty segment", key, got)
+			}
+			return
+		}
+
+		// Otherwise the result must be what walking the nested maps gives
+		var want any = cs.config
+		for _, segment := range strings.Split(key, ".") {
+			section, ok := want.(map[string]any)
+			if !ok {
+				want = nil
+				break
+			}
+			want = section[segment]
+		}
+		if !sameValue(got, want) {
+			t.Fatalf("getNestedValue(%q) = %v, want %v", key, got, want)
+		}
+	})
+}
+
+// sameValue compares config values, which include uncomparable maps
+func sameValue(a, b any) bool {
+	aMap, aIsMap := a.(map[string]any)
+	bMap, bIsMap := b.(map[string]any)
+	if aIsMap || bIsMap {
+		return aIsMap && bIsMap && len(aMap) == len(bMap)
+	}
+	return a == b
+}
@@ -0,0 +1,106 @@
+package xcomp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// Unmarshal decodes the config section at key ("" for the whole config) into
+// target, a pointer to a struct. Fields are matched by their yaml tag, or by name
+// ignoring case, and environment overrides of individual keys apply as with Get.
+// Values are converted as for config tags ("30s" or seconds for durations,
+// numeric strings from the environment); keys absent from the section leave the
+// field as it was, so defaults can be set before calling Unmarshal:
+//
+//	dbConfig := DatabaseConfig{MaxConnections: 25}
+//	err := config.Unmarshal("database", &dbConfig)
+func (cs *ConfigService) Unmarshal(key string, target any) error {
+	section, err := cs.section(key)
+	if err != nil {
+		return err
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           target,
+		TagName:          "yaml",
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			durationDecodeHook,
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal config '%s': %w", key, err)
+	}
+	if err := decoder.Decode(section); err != nil {
+		return fmt.Errorf("cannot unmarshal config '%s': %w", key, err)
+	}
+	return nil
+}
+
+// section returns the map at key with every leaf read through Get, so environment
+// overrides replace the file values
+func (cs *ConfigService) section(key string) (map[string]any, error) {
+	var tree map[string]any
+	if key == "" {
+		cs.mu.RLock()
+		tree = make(map[string]any, len(cs.config))
+		for k, v := range cs.config {
+			tree[k] = v
+		}
+		cs.mu.RUnlock()
+	} else {
+		value := cs.Get(key)
+		if value == nil {
+			return nil, fmt.Errorf("config section '%s' not found", key)
+		}
+		section, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config key '%s' is a %T, not a section", key, value)
+		}
+		tree = section
+	}
+
+	leaves := make(map[string]any)
+	flattenConfig("", tree, leaves)
+
+	section := make(map[string]any)
+	for path, value := range leaves {
+		fullKey := path
+		if key != "" {
+			fullKey = key + "." + path
+		}
+		if override := cs.Get(fullKey); override != nil {
+			value = override
+		}
+		setNested(section, strings.Split(path, "."), value)
+	}
+	return section, nil
+}
+
+func setNested(tree map[string]any, path []string, value any) {
+	for _, segment := range path[:len(path)-1] {
+		next, ok := tree[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			tree[segment] = next
+		}
+		tree = next
+	}
+	tree[path[len(path)-1]] = value
+}
+
+// durationDecodeHook accepts "30s", "1500ms" and bare seconds for time.Duration fields
+func durationDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != durationType || from == durationType {
+		return data, nil
+	}
+	converted, err := convertConfigValue(data, durationType)
+	if err != nil {
+		return nil, err
+	}
+	return converted.Interface(), nil
+}
@@ -0,0 +1,95 @@
+package xcomp
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDelay lets a burst of writes to a config file settle before it is
+// re-read, so an editor's save is not parsed half written
+const configReloadDelay = 100 * time.Millisecond
+
+// OnChange registers fn to run after a Reload, manual or automatic, that changed
+// at least one key
+func (cs *ConfigService) OnChange(fn func(cs *ConfigService)) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.onChange = append(cs.onChange, fn)
+}
+
+// Close stops watching the config files; it is a no-op unless AutoReload is set
+func (cs *ConfigService) Close() error {
+	cs.mu.Lock()
+	watcher := cs.watcher
+	cs.watcher = nil
+	cs.mu.Unlock()
+
+	if watcher == nil {
+		return nil
+	}
+	return watcher.Close()
+}
+
+// watch reloads the config whenever one of its files changes. The directories are
+// watched rather than the files so that editors which save by renaming a new file
+// over the old one keep being followed.
+func (cs *ConfigService) watch(onError func(error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config files: %w", err)
+	}
+
+	files := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, path := range cs.paths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch config file %s: %w", path, err)
+		}
+		files[absolute] = true
+		dirs[filepath.Dir(absolute)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch config directory %s: %w", dir, err)
+		}
+	}
+
+	cs.mu.Lock()
+	cs.watcher = watcher
+	cs.mu.Unlock()
+
+	go func() {
+		var pending <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				absolute, _ := filepath.Abs(event.Name)
+				if files[absolute] && !event.Has(fsnotify.Chmod) {
+					pending = time.After(configReloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				if onError != nil {
+					onError(err)
+				}
+			case <-pending:
+				pending = nil
+				if err := cs.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
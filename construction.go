@@ -0,0 +1,73 @@
+package xcomp
+
+import "time"
+
+// slowConstruction is the construction time from which LogConstructions logs at
+// info rather than debug level
+const slowConstruction = 100 * time.Millisecond
+
+// Construction records the first build of a lazy singleton. Duration includes
+// resolving the dependencies the factory builds on the way.
+type Construction struct {
+	Service  string
+	Duration time.Duration
+}
+
+// ConstructionObserver is called once for every lazy singleton the container builds
+type ConstructionObserver func(Construction)
+
+// ObserveConstructions registers observer for lazy singleton constructions. Those
+// that happened before, such as eager providers built during registration, are
+// replayed to it first, so an observer added once the logger is available still
+// sees the whole startup.
+func (c *Container) ObserveConstructions(observer ConstructionObserver) {
+	c.mutex.Lock()
+	past := append([]Construction(nil), c.constructions...)
+	c.observers = append(c.observers, observer)
+	c.mutex.Unlock()
+
+	for _, construction := range past {
+		observer(construction)
+	}
+}
+
+// construct resolves a lazy singleton that wasn't built yet and reports the
+// construction when this call is the one that ran the factory
+func (c *Container) construct(name string, lazy *lazyService) any {
+	start := time.Now()
+	instance, built := lazy.build()
+	if !built {
+		return instance
+	}
+
+	construction := Construction{Service: name, Duration: time.Since(start)}
+	c.mutex.Lock()
+	c.constructions = append(c.constructions, construction)
+	observers := append([]ConstructionObserver(nil), c.observers...)
+	c.mutex.Unlock()
+
+	for _, observer := range observers {
+		observer(construction)
+	}
+	return instance
+}
+
+// LogConstructions logs each construction, at info level when it took long enough
+// to be felt by the request that triggered it, and adds its duration to the
+// container_construction_milliseconds_total counter. metrics may be nil.
+func LogConstructions(logger Logger, metrics *Metrics) ConstructionObserver {
+	return func(construction Construction) {
+		metrics.Counter("container_construction_milliseconds_total", "service", construction.Service).
+			Add(construction.Duration.Milliseconds())
+
+		fields := []LogField{
+			String("service", construction.Service),
+			Duration("duration", construction.Duration),
+		}
+		if construction.Duration >= slowConstruction {
+			logger.Info("Slow service construction", fields...)
+			return
+		}
+		logger.Debug("Service constructed", fields...)
+	}
+}
@@ -0,0 +1,214 @@
+package xcomp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	containerType = reflect.TypeOf((*Container)(nil))
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// constructor is a validated constructor function, e.g.
+// func(interfaces.OrderRepository, xcomp.Logger) *OrderService
+type constructor struct {
+	fn         reflect.Value
+	paramNames []string
+}
+
+// newConstructor checks that ctor is a function returning a service, optionally with
+// an error, and that paramNames, when given, name every parameter
+func newConstructor(ctor any, paramNames []string) (*constructor, error) {
+	fn := reflect.ValueOf(ctor)
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, fmt.Errorf("constructor must be a function, got %T", ctor)
+	}
+
+	fnType := fn.Type()
+	switch {
+	case fnType.IsVariadic():
+		return nil, fmt.Errorf("constructor %s must not be variadic", fnType)
+	case fnType.NumOut() == 1 && fnType.Out(0) != errorType:
+	case fnType.NumOut() == 2 && fnType.Out(1) == errorType:
+	default:
+		return nil, fmt.Errorf("constructor %s must return a service, optionally followed by an error", fnType)
+	}
+
+	if len(paramNames) > 0 && len(paramNames) != fnType.NumIn() {
+		return nil, fmt.Errorf("constructor %s has %d parameters but %d names were given", fnType, fnType.NumIn(), len(paramNames))
+	}
+
+	return &constructor{fn: fn, paramNames: paramNames}, nil
+}
+
+// serviceType is the type the constructor declares it returns
+func (ctor *constructor) serviceType() reflect.Type {
+	return ctor.fn.Type().Out(0)
+}
+
+// call resolves every parameter from the container and invokes the constructor
+func (ctor *constructor) call(c *Container) (any, error) {
+	fnType := ctor.fn.Type()
+	args := make([]reflect.Value, fnType.NumIn())
+
+	for i := range args {
+		paramType := fnType.In(i)
+
+		var (
+			service any
+			err     error
+		)
+		switch {
+		case len(ctor.paramNames) > 0:
+			service, err = c.resolveName(ctor.paramNames[i], paramType)
+		case paramType == containerType:
+			service = c
+		default:
+			service, err = c.resolveType(paramType)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d (%s): %w", i, paramType, err)
+		}
+
+		args[i] = reflect.ValueOf(service)
+	}
+
+	results := ctor.fn.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return nil, results[1].Interface().(error)
+	}
+	return results[0].Interface(), nil
+}
+
+func (c *Container) resolveName(name string, target reflect.Type) (any, error) {
+	service := c.Get(name)
+	if service == nil {
+		return nil, fmt.Errorf("service '%s' not found", name)
+	}
+	if !reflect.TypeOf(service).AssignableTo(target) {
+		return nil, fmt.Errorf("service '%s' has type %T, not assignable to %s", name, service, target)
+	}
+	return service, nil
+}
+
+// resolveType finds the single service assignable to target. Only services whose
+// type is known without running a factory are considered: registered instances,
+// constructors (by their declared return type) and factories already resolved.
+func (c *Container) resolveType(target reflect.Type) (any, error) {
+	var candidates []string
+	seen := make(map[string]bool)
+	for scope := c; scope != nil; scope = scope.parent {
+		scope.mutex.RLock()
+		for name, service := range scope.services {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if serviceType := knownServiceType(service); serviceType != nil && serviceType.AssignableTo(target) {
+				candidates = append(candidates, name)
+			}
+		}
+		scope.mutex.RUnlock()
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no service registered for type %s; register it with a constructor or resolve it by name", target)
+	case 1:
+		return c.resolveName(candidates[0], target)
+	default:
+		sort.Strings(candidates)
+		return nil, fmt.Errorf("type %s is ambiguous, candidates: %s", target, strings.Join(candidates, ", "))
+	}
+}
+
+func knownServiceType(service any) reflect.Type {
+	lazy, ok := service.(*lazyService)
+	if !ok {
+		if service == nil {
+			return nil
+		}
+		return reflect.TypeOf(service)
+	}
+
+	if lazy.declaredType != nil {
+		return lazy.declaredType
+	}
+	if instance, ok := lazy.resolved(); ok && instance != nil {
+		return reflect.TypeOf(instance)
+	}
+	return nil
+}
+
+// RegisterConstructor registers a singleton built by calling ctor with its parameters
+// resolved from the container: by type, or by the given paramNames in order. A
+// *Container parameter receives the container itself. ctor may return an error as its
+// second result. Resolution happens on first Get; a parameter that can't be resolved
+// panics there with the constructor and parameter named, like a failed Inject in a
+// factory. RegisterConstructor itself only fails when ctor has the wrong shape.
+func (c *Container) RegisterConstructor(name string, ctor any, paramNames ...string) error {
+	if name == "" {
+		return fmt.Errorf("provider name cannot be empty")
+	}
+
+	constructor, err := newConstructor(ctor, paramNames)
+	if err != nil {
+		return fmt.Errorf("constructor '%s': %w", name, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setLocked(name, constructor.lazyService(name, c))
+	return nil
+}
+
+func (ctor *constructor) lazyService(name string, c *Container) *lazyService {
+	return &lazyService{
+		factory:      ctor.factory(name),
+		container:    c,
+		declaredType: ctor.serviceType(),
+	}
+}
+
+func (ctor *constructor) factory(name string) func(*Container) any {
+	return func(c *Container) any {
+		service, err := ctor.call(c)
+		if err != nil {
+			panic(fmt.Sprintf("failed to construct '%s': %v", name, err))
+		}
+		return service
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// RegisterType registers a lazy factory whose declared type is T, usually an
+// interface, so the service can be found with ResolveByType[T] before it is built
+func RegisterType[T any](c *Container, name string, factory func(*Container) T) error {
+	return c.RegisterConstructor(name, factory)
+}
+
+// NewTypedProvider is RegisterType for modules:
+//
+//	AddProvider(xcomp.NewTypedProvider[interfaces.ProductService]("ProductService", newProductService))
+func NewTypedProvider[T any](name string, factory func(*Container) T) Provider {
+	return NewConstructorProvider(name, factory)
+}
+
+// ResolveByType returns the single service assignable to T, without knowing its name.
+// Services registered by type or constructor, and instances, are matched; plain
+// factories only once they have been resolved by name. More than one match is an
+// error listing the candidates.
+func ResolveByType[T any](c *Container) (T, error) {
+	var zero T
+	service, err := c.resolveType(typeOf[T]())
+	if err != nil {
+		return zero, err
+	}
+	return service.(T), nil
+}
@@ -3,12 +3,43 @@ package xcomp
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
+// ContainerServiceName is the reserved inject name for the container itself:
+//
+//	Container *xcomp.Container `inject:"container"`
+//
+// It is meant for services that resolve dependencies dynamically; prefer injecting
+// concrete services wherever the dependency is known up front.
+const ContainerServiceName = "container"
+
 type Container struct {
 	services map[string]any
-	mutex    sync.RWMutex
+	// parent is set for scopes, see NewScope
+	parent       *Container
+	moduleFilter func(name string) bool
+	strictInject bool
+	warnings     []string
+	// groups maps a group to the generated service names of its members
+	groups     map[string][]string
+	resolution resolutionTracker
+	// constructions are kept so observers added later are replayed the startup
+	constructions []Construction
+	observers     []ConstructionObserver
+	// order is the registration order of names, used to close services in reverse;
+	// it also holds the keys of namespaces
+	order []string
+	// namespaces are the containers of modules with exports, see ModuleBuilder.Export
+	namespaces map[string]*Container
+	// dependencies are the edges of DependencyGraph
+	dependencies map[string][]string
+	// lifecycle holds the module hooks, see StartModules
+	lifecycle moduleLifecycles
+	disposed  bool
+	mutex     sync.RWMutex
 }
 
 func NewContainer() *Container {
@@ -20,13 +51,21 @@ func NewContainer() *Container {
 func (c *Container) Register(name string, service any) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.services[name] = service
+	c.setLocked(name, service)
 }
 
 func (c *Container) RegisterSingleton(name string, factory func(*Container) any) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.services[name] = &lazyService{factory: factory, container: c}
+	c.setLocked(name, &lazyService{factory: factory, container: c})
+}
+
+// setLocked registers service under name; the caller holds the write lock
+func (c *Container) setLocked(name string, service any) {
+	if _, exists := c.services[name]; !exists {
+		c.order = append(c.order, name)
+	}
+	c.services[name] = service
 }
 
 type lazyService struct {
@@ -34,21 +73,47 @@ type lazyService struct {
 	container *Container
 	instance  any
 	once      sync.Once
+	done      atomic.Bool
+	// declaredType is known up front for constructors, letting them be resolved by type
+	declaredType reflect.Type
 }
 
 func (ls *lazyService) getInstance() any {
+	instance, _ := ls.build()
+	return instance
+}
+
+// build returns the instance and whether this call ran the factory
+func (ls *lazyService) build() (instance any, built bool) {
 	ls.once.Do(func() {
 		ls.instance = ls.factory(ls.container)
+		ls.done.Store(true)
+		built = true
 	})
-	return ls.instance
+	return ls.instance, built
+}
+
+// resolved returns the instance without running the factory
+func (ls *lazyService) resolved() (any, bool) {
+	if !ls.done.Load() {
+		return nil, false
+	}
+	return ls.instance, true
 }
 
 func (c *Container) Get(name string) any {
-	c.mutex.RLock()
-	service := c.services[name]
-	c.mutex.RUnlock()
+	// Only records an edge while a factory is running; otherwise this is one
+	// atomic load, keeping lookups of resolved singletons lock-free here
+	c.recordDependency(name)
+	service, _ := c.lookup(name)
 
 	if lazyService, ok := service.(*lazyService); ok {
+		if _, done := lazyService.resolved(); !done {
+			// Tracked by the container owning the singleton, which may be a parent scope
+			owner := lazyService.container
+			defer owner.resolution.enter(name)()
+			return owner.construct(name, lazyService)
+		}
 		return lazyService.getInstance()
 	}
 	return service
@@ -80,24 +145,110 @@ func (c *Container) Inject(target any) error {
 		return fmt.Errorf("target must be a pointer")
 	}
 
-	targetValue = targetValue.Elem()
+	if targetValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must point to a struct")
+	}
+
+	if err := c.injectFields(targetValue.Elem()); err != nil {
+		return err
+	}
+
+	if setterInjectable, ok := target.(SetterInjectable); ok {
+		if err := c.injectSetters(setterInjectable); err != nil {
+			return err
+		}
+	}
+
+	if initializable, ok := target.(Initializable); ok {
+		if err := initializable.Initialize(); err != nil {
+			return &InitializeError{Target: fmt.Sprintf("%T", target), Err: err}
+		}
+	}
+	return nil
+}
+
+// Initializable is implemented by structs that need setup once their dependencies
+// are injected, such as opening a connection; Inject calls Initialize after
+// setting every tagged field
+type Initializable interface {
+	Initialize() error
+}
+
+// InitializeError is returned by Inject when the target's Initialize fails, as
+// opposed to a dependency that could not be injected
+type InitializeError struct {
+	Target string
+	Err    error
+}
+
+func (e *InitializeError) Error() string {
+	return fmt.Sprintf("failed to initialize %s: %v", e.Target, e.Err)
+}
+
+func (e *InitializeError) Unwrap() error {
+	return e.Err
+}
+
+func (c *Container) injectFields(targetValue reflect.Value) error {
 	targetType := targetValue.Type()
 
 	for i := 0; i < targetValue.NumField(); i++ {
 		field := targetValue.Field(i)
 		fieldType := targetType.Field(i)
 
-		injectTag := fieldType.Tag.Get("inject")
+		if configKey := fieldType.Tag.Get("config"); configKey != "" {
+			if !field.CanSet() {
+				if c.isStrictInject() {
+					return fmt.Errorf("field '%s' has config tag '%s' but is not settable", fieldType.Name, configKey)
+				}
+				continue
+			}
+			if err := c.injectConfig(field, fieldType.Name, configKey); err != nil {
+				return err
+			}
+			continue
+		}
+
+		injectTag, optional := parseInjectTag(fieldType.Tag.Get("inject"))
 		if injectTag == "" {
+			// Recurse into embedded structs so injected fields on a shared base are wired too
+			if fieldType.Anonymous {
+				if err := c.injectEmbedded(field); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
 		if !field.CanSet() {
+			if c.isStrictInject() {
+				return fmt.Errorf("field '%s' has inject tag '%s' but is not settable; export it or use method injection", fieldType.Name, injectTag)
+			}
+			continue
+		}
+
+		if injectTag == ContainerServiceName {
+			if field.Type() != reflect.TypeOf(c) {
+				return fmt.Errorf("field '%s' must be *xcomp.Container to inject '%s'", fieldType.Name, ContainerServiceName)
+			}
+			field.Set(reflect.ValueOf(c))
+			continue
+		}
+
+		if group, ok := parseGroupTag(injectTag); ok {
+			members, err := c.groupValue(group, field.Type())
+			if err != nil {
+				return fmt.Errorf("field '%s': %w", fieldType.Name, err)
+			}
+			field.Set(members)
 			continue
 		}
 
 		service := c.Get(injectTag)
 		if service == nil {
+			if optional {
+				continue
+			}
 			return fmt.Errorf("service '%s' not found for field '%s'", injectTag, fieldType.Name)
 		}
 
@@ -112,17 +263,123 @@ func (c *Container) Inject(target any) error {
 	return nil
 }
 
-func (c *Container) AutoWire(target any) error {
-	return c.Inject(target)
+// parseInjectTag splits `inject:"Metrics,optional"` into the service name and
+// whether a missing service leaves the field at its zero value instead of failing
+func parseInjectTag(tag string) (name string, optional bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if strings.TrimSpace(option) == "optional" {
+			optional = true
+		}
+	}
+	return strings.TrimSpace(name), optional
 }
 
-func (c *Container) ListServices() []string {
+func (c *Container) isStrictInject() bool {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
+	return c.strictInject
+}
+
+func (c *Container) injectEmbedded(field reflect.Value) error {
+	switch field.Kind() {
+	case reflect.Struct:
+		return c.injectFields(field)
+	case reflect.Ptr:
+		if field.IsNil() || field.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		return c.injectFields(field.Elem())
+	}
+	return nil
+}
+
+// MustInject is Inject for factories, where a missing dependency is a wiring bug:
+// it panics instead of leaving a nil field to fail on first use
+func (c *Container) MustInject(target any) {
+	if err := c.Inject(target); err != nil {
+		panic(fmt.Sprintf("failed to inject %T: %v", target, err))
+	}
+}
+
+// MustGet is Get for services that must exist: it panics naming the service when
+// nothing is registered under name or its factory returned nil
+func (c *Container) MustGet(name string) any {
+	service := c.Get(name)
+	if service == nil {
+		panic(fmt.Sprintf("service '%s' is not registered or resolved to nil", name))
+	}
+	return service
+}
+
+// MustResolve is MustGet with the type assertion, panicking with the stored type
+// when it isn't a T:
+//
+//	orderService := xcomp.MustResolve[interfaces.OrderService](c, "OrderService")
+func MustResolve[T any](c *Container, name string) T {
+	service := c.MustGet(name)
+	typed, ok := service.(T)
+	if !ok {
+		panic(fmt.Sprintf("service '%s' is %T, not %s", name, service, typeOf[T]()))
+	}
+	return typed
+}
+
+// SetStrictInject makes Inject fail on inject tags it would otherwise skip, such as
+// tags on unexported fields that reflection cannot set
+func (c *Container) SetStrictInject(strict bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.strictInject = strict
+}
+
+func (c *Container) AutoWire(target any) error {
+	return c.Inject(target)
+}
 
-	services := make([]string, 0, len(c.services))
-	for name := range c.services {
-		services = append(services, name)
+// ListServices lists the names Get can resolve, including those a scope inherits
+func (c *Container) ListServices() []string {
+	var services []string
+	seen := make(map[string]bool)
+	for scope := c; scope != nil; scope = scope.parent {
+		scope.mutex.RLock()
+		for name := range scope.services {
+			if !seen[name] {
+				seen[name] = true
+				services = append(services, name)
+			}
+		}
+		scope.mutex.RUnlock()
 	}
 	return services
 }
+
+// Handle is a typed reference to a service resolved once, typically at startup.
+// Reading it afterwards needs neither the container lock nor a type assertion,
+// which makes it suitable for services accessed on every request.
+type Handle[T any] struct {
+	name  string
+	value T
+}
+
+func NewHandle[T any](c *Container, name string) (*Handle[T], error) {
+	service := c.Get(name)
+	if service == nil {
+		return nil, fmt.Errorf("service '%s' not found", name)
+	}
+
+	value, ok := service.(T)
+	if !ok {
+		return nil, fmt.Errorf("service '%s' has type %T, not %s", name, service, reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	return &Handle[T]{name: name, value: value}, nil
+}
+
+func (h *Handle[T]) Get() T {
+	return h.value
+}
+
+func (h *Handle[T]) Name() string {
+	return h.name
+}
@@ -0,0 +1,159 @@
+package xcomp
+
+import (
+	"strings"
+	"testing"
+)
+
+type benchService struct {
+	name string
+}
+
+func newBenchContainer(b *testing.B) *Container {
+	b.Helper()
+	c := NewContainer()
+	c.RegisterSingleton("Service", func(*Container) any {
+		return &benchService{name: "service"}
+	})
+	if c.Get("Service") == nil {
+		b.Fatal("service did not resolve")
+	}
+	return c
+}
+
+func BenchmarkGet(b *testing.B) {
+	c := newBenchContainer(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := c.Get("Service").(*benchService); !ok {
+			b.Fatal("unexpected service type")
+		}
+	}
+}
+
+func BenchmarkHandleGet(b *testing.B) {
+	c := newBenchContainer(b)
+	handle, err := NewHandle[*benchService](c, "Service")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if handle.Get() == nil {
+			b.Fatal("handle returned nil")
+		}
+	}
+}
+
+type baseService struct {
+	Logger Logger `inject:"Logger"`
+}
+
+type embeddingService struct {
+	baseService
+	Name string
+}
+
+type embeddingPointerService struct {
+	*baseService
+}
+
+func newLoggerContainer() (*Container, Logger) {
+	logger := NewDevelopmentLogger()
+	c := NewContainer()
+	c.Register("Logger", logger)
+	return c, logger
+}
+
+func TestInjectEmbeddedStruct(t *testing.T) {
+	c, logger := newLoggerContainer()
+
+	service := &embeddingService{}
+	if err := c.Inject(service); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if service.Logger != logger {
+		t.Fatalf("embedded Logger = %v, want the registered logger", service.Logger)
+	}
+}
+
+func TestInjectEmbeddedPointer(t *testing.T) {
+	c, logger := newLoggerContainer()
+
+	service := &embeddingPointerService{baseService: &baseService{}}
+	if err := c.Inject(service); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if service.Logger != logger {
+		t.Fatalf("embedded Logger = %v, want the registered logger", service.Logger)
+	}
+}
+
+func TestInjectNilEmbeddedPointer(t *testing.T) {
+	c, _ := newLoggerContainer()
+
+	service := &embeddingPointerService{}
+	if err := c.Inject(service); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if service.baseService != nil {
+		t.Fatal("nil embedded pointer should be left nil")
+	}
+}
+
+func TestParseInjectTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		name     string
+		optional bool
+	}{
+		{tag: "Logger", name: "Logger"},
+		{tag: "Metrics,optional", name: "Metrics", optional: true},
+		{tag: " Metrics , optional ", name: "Metrics", optional: true},
+		{tag: "Metrics,", name: "Metrics"},
+		{tag: "", name: ""},
+	}
+	for _, tt := range tests {
+		name, optional := parseInjectTag(tt.tag)
+		if name != tt.name || optional != tt.optional {
+			t.Errorf("parseInjectTag(%q) = (%q, %v), want (%q, %v)", tt.tag, name, optional, tt.name, tt.optional)
+		}
+	}
+}
+
+type optionalDependencyService struct {
+	Logger  Logger   `inject:"Logger"`
+	Metrics *Metrics `inject:"Metrics,optional"`
+}
+
+func TestInjectToleratesMissingOptionalDependency(t *testing.T) {
+	c, logger := newLoggerContainer()
+
+	service := &optionalDependencyService{}
+	if err := c.Inject(service); err != nil {
+		t.Fatalf("Inject with only the optional dependency missing: %v", err)
+	}
+	if service.Logger != logger {
+		t.Fatalf("Logger = %v, want the registered logger", service.Logger)
+	}
+	if service.Metrics != nil {
+		t.Fatalf("Metrics = %v, want nil when no service is registered", service.Metrics)
+	}
+}
+
+func TestInjectFailsOnMissingRequiredDependency(t *testing.T) {
+	c := NewContainer()
+	c.Register("Metrics", NewMetrics())
+
+	err := c.Inject(&optionalDependencyService{})
+	if err == nil {
+		t.Fatal("Inject succeeded with the required Logger missing")
+	}
+	if !strings.Contains(err.Error(), "'Logger'") {
+		t.Fatalf("Inject error %q does not name the missing service", err)
+	}
+}
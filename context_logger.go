@@ -0,0 +1,114 @@
+package xcomp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const TraceparentHeader = "traceparent"
+
+type loggerKey struct{}
+
+type traceIDKey struct{}
+
+// nopLogger is what LoggerFromContext returns for contexts without a logger
+var nopLogger Logger = &ZapLogger{
+	logger: zap.NewNop(),
+	sugar:  zap.NewNop().Sugar(),
+	level:  zap.NewAtomicLevel(),
+}
+
+func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the request-scoped logger stored by the logger
+// middleware, which already carries the request's ContextFields. Without one it
+// returns a logger that discards everything, so callers never check for nil.
+func LoggerFromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	return nopLogger
+}
+
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID taken from the W3C traceparent header, or ""
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// ContextFields returns log fields for the request ID, trace ID and tenant stored
+// in ctx, leaving out those that are not set
+func ContextFields(ctx context.Context) []LogField {
+	var fields []LogField
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, String("request_id", requestID))
+	}
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, String("trace_id", traceID))
+	}
+	if tenantID := TenantFromContext(ctx); tenantID != "" {
+		fields = append(fields, String("tenant_id", tenantID))
+	}
+	return fields
+}
+
+// NewLoggerMiddleware stores a request-scoped logger in the user context, derived
+// from logger with the request's ContextFields, for LoggerFromContext(c.UserContext()).
+// It assigns a request ID like the request ID middleware when none is set yet and
+// reads the trace ID from the traceparent header; register it after the tenant
+// middleware for the tenant to be included.
+func NewLoggerMiddleware(logger Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		if RequestIDFromContext(ctx) == "" {
+			requestID := c.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			c.Set(RequestIDHeader, requestID)
+			c.Locals("request_id", requestID)
+			ctx = ContextWithRequestID(ctx, requestID)
+		}
+
+		if traceID := parseTraceparent(c.Get(TraceparentHeader)); traceID != "" {
+			ctx = ContextWithTraceID(ctx, traceID)
+		}
+
+		c.SetUserContext(ContextWithLogger(ctx, logger.With(ContextFields(ctx)...)))
+		return c.Next()
+	}
+}
+
+// parseTraceparent extracts the trace ID from "00-<trace-id>-<parent-id>-<flags>",
+// returning "" for malformed headers and the all-zero invalid trace ID
+func parseTraceparent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	traceID := strings.ToLower(parts[1])
+	if strings.Trim(traceID, "0") == "" {
+		return ""
+	}
+	for _, r := range traceID {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return traceID
+}
@@ -0,0 +1,120 @@
+package xcomp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// maxDiffDepth bounds how far Diff descends into nested structs
+const maxDiffDepth = 2
+
+// Diff compares two snapshots of the same struct type and returns the changed fields
+// as name -> [old, new]. Pointers are dereferenced, nested structs are compared field
+// by field up to a shallow depth ("address.city"), and fields are named after their
+// json tag when present. Values that are not structs of the same type are compared as
+// a whole under the empty name.
+func Diff(before, after any) map[string][2]any {
+	changes := make(map[string][2]any)
+
+	beforeValue := indirectValue(reflect.ValueOf(before))
+	afterValue := indirectValue(reflect.ValueOf(after))
+
+	if !beforeValue.IsValid() || !afterValue.IsValid() ||
+		beforeValue.Type() != afterValue.Type() || beforeValue.Kind() != reflect.Struct {
+		if !valuesEqual(beforeValue, afterValue) {
+			changes[""] = [2]any{interfaceOf(beforeValue), interfaceOf(afterValue)}
+		}
+		return changes
+	}
+
+	diffStruct(changes, "", beforeValue, afterValue, 0)
+	return changes
+}
+
+func diffStruct(changes map[string][2]any, prefix string, before, after reflect.Value, depth int) {
+	structType := before.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := diffFieldName(field)
+		if name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		beforeField := indirectValue(before.Field(i))
+		afterField := indirectValue(after.Field(i))
+
+		if depth < maxDiffDepth && isNestedStruct(beforeField) && isNestedStruct(afterField) {
+			diffStruct(changes, name, beforeField, afterField, depth+1)
+			continue
+		}
+
+		if !valuesEqual(beforeField, afterField) {
+			changes[name] = [2]any{interfaceOf(beforeField), interfaceOf(afterField)}
+		}
+	}
+}
+
+func diffFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// isNestedStruct reports structs worth descending into; structs with no exported
+// fields such as time.Time are compared as values
+func isNestedStruct(value reflect.Value) bool {
+	if !value.IsValid() || value.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func indirectValue(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func valuesEqual(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	// Prefer the type's own notion of equality, e.g. time.Time ignores the monotonic clock
+	if equal, ok := a.Type().MethodByName("Equal"); ok &&
+		equal.Type.NumIn() == 2 && equal.Type.In(1) == a.Type() &&
+		equal.Type.NumOut() == 1 && equal.Type.Out(0).Kind() == reflect.Bool {
+		return equal.Func.Call([]reflect.Value{a, b})[0].Bool()
+	}
+
+	return reflect.DeepEqual(interfaceOf(a), interfaceOf(b))
+}
+
+func interfaceOf(value reflect.Value) any {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
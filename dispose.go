@@ -0,0 +1,96 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Disposable is implemented by services holding resources, such as connection
+// pools, that must be released on shutdown
+type Disposable interface {
+	Close() error
+}
+
+// closer covers Close methods without an error result, e.g. *pgxpool.Pool
+type closer interface {
+	Close()
+}
+
+// Shutdown closes every lazy singleton the container has built, in reverse
+// registration order, and returns the Close errors joined. Singletons never
+// resolved are not built just to be closed, and instances passed to Register or
+// AddService are left to whoever created them. Shutdown stops early when ctx is
+// done and closes nothing when called again.
+func (c *Container) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, closer := range c.takeClosers() {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown interrupted before closing '%s': %w", closer.name, err))
+			break
+		}
+		if err := closer.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close '%s': %w", closer.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type namedCloser struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// takeClosers marks the container disposed and returns a closer for each built
+// service that holds resources, in the order they must run. It returns nil
+// once the container is disposed.
+func (c *Container) takeClosers() []namedCloser {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.disposed {
+		return nil
+	}
+	c.disposed = true
+
+	var closers []namedCloser
+	for i := len(c.order) - 1; i >= 0; i-- {
+		name := c.order[i]
+		if namespace, ok := c.namespaces[name]; ok {
+			closers = append(closers, namedCloser{name: name, close: namespace.Shutdown})
+			continue
+		}
+		lazy, ok := c.services[name].(*lazyService)
+		if !ok {
+			continue
+		}
+		instance, done := lazy.resolved()
+		if !done || isNil(instance) {
+			continue
+		}
+		switch s := instance.(type) {
+		case Disposable:
+			closers = append(closers, namedCloser{name: name, close: func(context.Context) error {
+				return s.Close()
+			}})
+		case closer:
+			closers = append(closers, namedCloser{name: name, close: func(context.Context) error {
+				s.Close()
+				return nil
+			}})
+		}
+	}
+	return closers
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package xcomp
+
+import "strings"
+
+// Environment identifies the deployment environment an application runs in
+type Environment string
+
+const (
+	EnvDevelopment Environment = "development"
+	EnvStaging     Environment = "staging"
+	EnvProduction  Environment = "production"
+	EnvTest        Environment = "test"
+)
+
+// ParseEnvironment normalizes common spellings ("dev", "prod", "stage") into an Environment.
+// Unknown values, such as a typo like "prd", fall back to EnvProduction so a
+// misconfigured deployment gets the strict defaults rather than debug output.
+func ParseEnvironment(value string) Environment {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "development", "dev", "local":
+		return EnvDevelopment
+	case "staging", "stage":
+		return EnvStaging
+	case "test", "testing":
+		return EnvTest
+	default:
+		return EnvProduction
+	}
+}
+
+func (e Environment) String() string {
+	return string(e)
+}
+
+func (e Environment) IsProduction() bool {
+	return e == EnvProduction
+}
+
+func (e Environment) IsDevelopment() bool {
+	return e == EnvDevelopment
+}
+
+// Environment returns the environment configured under app.environment.
+// When app.environment is absent the legacy logging.development flag is honored,
+// so older configs keep their behavior; otherwise production is assumed.
+func (cs *ConfigService) Environment() Environment {
+	if value := cs.Get("app.environment"); value != nil {
+		return ParseEnvironment(cs.GetString("app.environment"))
+	}
+
+	if cs.Get("logging.development") != nil {
+		if cs.GetBool("logging.development") {
+			return EnvDevelopment
+		}
+		return EnvProduction
+	}
+
+	return EnvProduction
+}
+
+func (cs *ConfigService) IsProduction() bool {
+	return cs.Environment().IsProduction()
+}
+
+func (cs *ConfigService) IsDevelopment() bool {
+	return cs.Environment().IsDevelopment()
+}
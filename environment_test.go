@@ -0,0 +1,50 @@
+package xcomp
+
+import "testing"
+
+func TestParseEnvironment(t *testing.T) {
+	tests := map[string]Environment{
+		"development": EnvDevelopment,
+		"Dev":         EnvDevelopment,
+		" local ":     EnvDevelopment,
+		"staging":     EnvStaging,
+		"stage":       EnvStaging,
+		"test":        EnvTest,
+		"production":  EnvProduction,
+		"PROD":        EnvProduction,
+		"prd":         EnvProduction,
+		"":            EnvProduction,
+	}
+	for value, want := range tests {
+		if got := ParseEnvironment(value); got != want {
+			t.Errorf("ParseEnvironment(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestConfigServiceEnvironment(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   Environment
+	}{
+		{name: "unset", config: "app:\n  name: api\n", want: EnvProduction},
+		{name: "app.environment", config: "app:\n  environment: development\n", want: EnvDevelopment},
+		{name: "typo", config: "app:\n  environment: prd\n", want: EnvProduction},
+		{name: "legacy development flag", config: "logging:\n  development: true\n", want: EnvDevelopment},
+		{name: "legacy production flag", config: "logging:\n  development: false\n", want: EnvProduction},
+		{
+			name:   "app.environment wins over the legacy flag",
+			config: "app:\n  environment: staging\nlogging:\n  development: true\n",
+			want:   EnvStaging,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewConfigService(writeConfigFile(t, tt.config))
+			if got := cs.Environment(); got != tt.want {
+				t.Fatalf("Environment() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package xcomp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Event is a published message; Payload holds the JSON-encoded value given to Publish
+type Event struct {
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode unmarshals the payload into v
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+type EventHandler func(ctx context.Context, event Event) error
+
+// EventBus delivers published events to the handlers subscribed to their topic.
+// Implementations differ in transport, not in API: payloads are always JSON-encoded,
+// so a handler behaves the same whether delivery is in-process or through Redis.
+type EventBus interface {
+	Publish(ctx context.Context, topic string, payload any) error
+	Subscribe(topic string, handler EventHandler)
+}
+
+// NewEvent encodes payload into an Event for topic
+func NewEvent(topic string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", topic, err)
+	}
+	return Event{Topic: topic, Payload: data}, nil
+}
+
+// EventHandlers is a topic -> handlers registry for EventBus implementations
+type EventHandlers struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+func (h *EventHandlers) Add(topic string, handler EventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string][]EventHandler)
+	}
+	h.handlers[topic] = append(h.handlers[topic], handler)
+}
+
+// Dispatch runs every handler for the event's topic in subscription order and
+// returns their errors joined; a failing handler doesn't stop the others
+func (h *EventHandlers) Dispatch(ctx context.Context, event Event) error {
+	h.mu.RLock()
+	handlers := append([]EventHandler(nil), h.handlers[event.Topic]...)
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s handler: %w", event.Topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// InProcessEventBus delivers events synchronously to handlers in the publishing
+// process. It is the fallback when Redis or the async subsystem is disabled and
+// needs no infrastructure, which also makes it the bus to use in tests.
+type InProcessEventBus struct {
+	handlers EventHandlers
+}
+
+func NewInProcessEventBus() *InProcessEventBus {
+	return &InProcessEventBus{}
+}
+
+func (b *InProcessEventBus) GetServiceName() string {
+	return "EventBus"
+}
+
+// Publish returns once every handler has run, with their errors joined
+func (b *InProcessEventBus) Publish(ctx context.Context, topic string, payload any) error {
+	event, err := NewEvent(topic, payload)
+	if err != nil {
+		return err
+	}
+	return b.handlers.Dispatch(ctx, event)
+}
+
+func (b *InProcessEventBus) Subscribe(topic string, handler EventHandler) {
+	b.handlers.Add(topic, handler)
+}
+
+var _ EventBus = (*InProcessEventBus)(nil)
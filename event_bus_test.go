@@ -0,0 +1,90 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type orderPlaced struct {
+	OrderID string  `json:"order_id"`
+	Total   float64 `json:"total"`
+}
+
+func TestInProcessEventBusDeliversSynchronously(t *testing.T) {
+	bus := NewInProcessEventBus()
+
+	var received []orderPlaced
+	var order []string
+	bus.Subscribe("order.placed", func(ctx context.Context, event Event) error {
+		var payload orderPlaced
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		received = append(received, payload)
+		order = append(order, "first")
+		return nil
+	})
+	bus.Subscribe("order.placed", func(ctx context.Context, event Event) error {
+		order = append(order, "second")
+		return nil
+	})
+	bus.Subscribe("order.cancelled", func(ctx context.Context, event Event) error {
+		t.Errorf("handler for %s ran for an order.placed event", event.Topic)
+		return nil
+	})
+
+	want := orderPlaced{OrderID: "o-1", Total: 42.5}
+	if err := bus.Publish(context.Background(), "order.placed", want); err != nil {
+		t.Fatalf("Publish returned %v", err)
+	}
+
+	// Delivery is synchronous, so the handlers have run by the time Publish returns
+	if len(received) != 1 || received[0] != want {
+		t.Fatalf("handler received %+v, want [%+v]", received, want)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("handlers ran as %v, want subscription order", order)
+	}
+}
+
+func TestInProcessEventBusJoinsHandlerErrors(t *testing.T) {
+	bus := NewInProcessEventBus()
+	errBoom := errors.New("boom")
+
+	ran := false
+	bus.Subscribe("order.placed", func(ctx context.Context, event Event) error {
+		return errBoom
+	})
+	bus.Subscribe("order.placed", func(ctx context.Context, event Event) error {
+		ran = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), "order.placed", orderPlaced{OrderID: "o-1"})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Publish returned %v, want it to wrap %v", err, errBoom)
+	}
+	if !ran {
+		t.Fatal("a failing handler stopped the next one from running")
+	}
+}
+
+func TestInProcessEventBusRejectsUnencodablePayload(t *testing.T) {
+	bus := NewInProcessEventBus()
+	bus.Subscribe("order.placed", func(ctx context.Context, event Event) error {
+		t.Error("handler ran for a payload that failed to encode")
+		return nil
+	})
+
+	if err := bus.Publish(context.Background(), "order.placed", make(chan int)); err == nil {
+		t.Fatal("Publish accepted a payload that can't be encoded")
+	}
+}
+
+func TestInProcessEventBusWithoutSubscribers(t *testing.T) {
+	bus := NewInProcessEventBus()
+	if err := bus.Publish(context.Background(), "order.placed", orderPlaced{}); err != nil {
+		t.Fatalf("Publish with no subscribers returned %v", err)
+	}
+}
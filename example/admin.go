@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"example/controllers"
+	"xcomp"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupAdminRoutes mounts the /admin endpoints, which are only enabled when
+// admin.token is set (ADMIN__TOKEN in the environment); requests authenticate
+// with it as a bearer token
+func setupAdminRoutes(app fiber.Router, container *xcomp.Container, configService *xcomp.ConfigService, logger xcomp.Logger) {
+	token := configService.GetString("admin.token")
+	if token == "" {
+		logger.Info("Admin endpoints disabled, set admin.token to enable them")
+		return
+	}
+
+	table := xcomp.NewRouteTable()
+	logLevel := xcomp.NewLogLevelHandler(logger)
+	table.Add("", xcomp.Route{Method: fiber.MethodGet, Path: "/log-level", Name: "admin.log_level.get", Handler: logLevel},
+		xcomp.Route{Method: fiber.MethodPut, Path: "/log-level", Name: "admin.log_level.set", Handler: logLevel})
+	table.Add("", xcomp.Route{Method: fiber.MethodGet, Path: "/dependencies", Name: "admin.dependencies", Handler: func(c *fiber.Ctx) error {
+		// DOT output for `dot -Tsvg`, JSON otherwise
+		if c.Query("format") == "dot" {
+			c.Set(fiber.HeaderContentType, "text/vnd.graphviz")
+			return container.ExportGraphviz(c)
+		}
+		return c.JSON(container.DependencyGraph())
+	}})
+	if jobAdmin, ok := container.Get("JobAdminController").(*controllers.JobAdminController); ok && jobAdmin != nil {
+		table.Register("/jobs", jobAdmin)
+	}
+
+	table.Mount(app.Group("/admin", requireBearerToken(token)))
+}
+
+func requireBearerToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		given, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": "a valid admin token is required",
+			})
+		}
+		return c.Next()
+	}
+}
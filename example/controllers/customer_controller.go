@@ -4,9 +4,10 @@ import (
 	"strconv"
 
 	"example/modules/customer/application/dto"
-	"example/modules/customer/domain/entities"
 	"example/modules/customer/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -19,6 +20,20 @@ func (cc *CustomerController) GetServiceName() string {
 	return "CustomerController"
 }
 
+// Routes are mounted under the /customers prefix
+func (cc *CustomerController) Routes() []xcomp.Route {
+	return []xcomp.Route{
+		{Method: fiber.MethodGet, Path: "/", Name: "customers.list", Handler: cc.ListCustomers},
+		{Method: fiber.MethodGet, Path: "/search", Name: "customers.search", Handler: cc.SearchCustomers},
+		{Method: fiber.MethodGet, Path: "/username/:username", Name: "customers.get_by_username", Handler: cc.GetCustomerByUsername},
+		{Method: fiber.MethodGet, Path: "/by-email", Name: "customers.get_by_email", Handler: cc.GetCustomerByEmail},
+		{Method: fiber.MethodGet, Path: "/:id", Name: "customers.get", Handler: cc.GetCustomer},
+		{Method: fiber.MethodPost, Path: "/", Name: "customers.create", Handler: cc.CreateCustomer},
+		{Method: fiber.MethodPut, Path: "/:id", Name: "customers.update", Handler: cc.UpdateCustomer},
+		{Method: fiber.MethodDelete, Path: "/:id", Name: "customers.delete", Handler: cc.DeleteCustomer},
+	}
+}
+
 func (cc *CustomerController) GetCustomer(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -29,18 +44,9 @@ func (cc *CustomerController) GetCustomer(c *fiber.Ctx) error {
 		})
 	}
 
-	customer, err := cc.CustomerService.GetCustomer(c.Context(), id)
+	customer, err := cc.CustomerService.GetCustomer(c.UserContext(), id)
 	if err != nil {
-		if err == entities.ErrCustomerNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Customer not found",
-				"message": "The requested customer does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -58,18 +64,9 @@ func (cc *CustomerController) GetCustomerByUsername(c *fiber.Ctx) error {
 		})
 	}
 
-	customer, err := cc.CustomerService.GetCustomerByUsername(c.Context(), username)
+	customer, err := cc.CustomerService.GetCustomerByUsername(c.UserContext(), username)
 	if err != nil {
-		if err == entities.ErrCustomerNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Customer not found",
-				"message": "The requested customer does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -87,18 +84,9 @@ func (cc *CustomerController) GetCustomerByEmail(c *fiber.Ctx) error {
 		})
 	}
 
-	customer, err := cc.CustomerService.GetCustomerByEmail(c.Context(), email)
+	customer, err := cc.CustomerService.GetCustomerByEmail(c.UserContext(), email)
 	if err != nil {
-		if err == entities.ErrCustomerNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Customer not found",
-				"message": "The requested customer does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -108,22 +96,13 @@ func (cc *CustomerController) GetCustomerByEmail(c *fiber.Ctx) error {
 }
 
 func (cc *CustomerController) ListCustomers(c *fiber.Ctx) error {
+	// The service clamps page and page_size into an xcomp.Page
 	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 32)
 	pageSize, _ := strconv.ParseInt(c.Query("page_size", "10"), 10, 32)
 
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
-	customers, err := cc.CustomerService.ListCustomers(c.Context(), int32(page), int32(pageSize))
+	customers, err := cc.CustomerService.ListCustomers(c.UserContext(), int32(page), int32(pageSize))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -150,12 +129,9 @@ func (cc *CustomerController) SearchCustomers(c *fiber.Ctx) error {
 		PageSize: int32(pageSize),
 	}
 
-	customers, err := cc.CustomerService.SearchCustomers(c.Context(), searchReq)
+	customers, err := cc.CustomerService.SearchCustomers(c.UserContext(), searchReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -166,25 +142,13 @@ func (cc *CustomerController) SearchCustomers(c *fiber.Ctx) error {
 
 func (cc *CustomerController) CreateCustomer(c *fiber.Ctx) error {
 	var req dto.CreateCustomerRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if err := xcomp.BindAndValidate(c, &req); err != nil {
+		return xcomp.WriteBindError(c, err)
 	}
 
-	customer, err := cc.CustomerService.CreateCustomer(c.Context(), &req)
+	customer, err := cc.CustomerService.CreateCustomer(c.UserContext(), &req)
 	if err != nil {
-		if err == entities.ErrCustomerUsernameExists || err == entities.ErrCustomerEmailExists {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error":   "Conflict",
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Failed to create customer",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -204,31 +168,13 @@ func (cc *CustomerController) UpdateCustomer(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateCustomerRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if err := xcomp.BindAndValidate(c, &req); err != nil {
+		return xcomp.WriteBindError(c, err)
 	}
 
-	customer, err := cc.CustomerService.UpdateCustomer(c.Context(), id, &req)
+	customer, err := cc.CustomerService.UpdateCustomer(c.UserContext(), id, &req)
 	if err != nil {
-		if err == entities.ErrCustomerNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Customer not found",
-				"message": "The requested customer does not exist",
-			})
-		}
-		if err == entities.ErrCustomerUsernameExists || err == entities.ErrCustomerEmailExists {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error":   "Conflict",
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Failed to update customer",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -247,18 +193,9 @@ func (cc *CustomerController) DeleteCustomer(c *fiber.Ctx) error {
 		})
 	}
 
-	err = cc.CustomerService.DeleteCustomer(c.Context(), id)
+	err = cc.CustomerService.DeleteCustomer(c.UserContext(), id, c.QueryBool("force", false))
 	if err != nil {
-		if err == entities.ErrCustomerNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Customer not found",
-				"message": "The requested customer does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Failed to delete customer",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"example/jobs"
+
+	"xcomp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hibiken/asynq"
+)
+
+// archivedScanPageSize is how many archived tasks are read from Redis at a time
+// while filtering by type
+const archivedScanPageSize = 500
+
+// JobAdminController lists background jobs that exhausted their retries, which
+// asynq archives, and puts selected ones back on their queue
+type JobAdminController struct {
+	inspector *asynq.Inspector
+}
+
+func NewJobAdminController(inspector *asynq.Inspector) *JobAdminController {
+	return &JobAdminController{inspector: inspector}
+}
+
+// Routes are mounted under the /admin/jobs prefix, behind admin authentication
+func (c *JobAdminController) Routes() []xcomp.Route {
+	return []xcomp.Route{
+		{Method: fiber.MethodGet, Path: "/archived", Name: "admin.jobs.archived", Handler: c.ListArchived},
+		{Method: fiber.MethodPost, Path: "/:id/requeue", Name: "admin.jobs.requeue", Handler: c.Requeue},
+	}
+}
+
+type ArchivedJobResponse struct {
+	ID           string          `json:"id"`
+	Queue        string          `json:"queue"`
+	Type         string          `json:"type"`
+	Payload      json.RawMessage `json:"payload"`
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+	LastError    string          `json:"last_error"`
+	LastFailedAt time.Time       `json:"last_failed_at"`
+}
+
+type ArchivedJobListResponse struct {
+	Jobs       []ArchivedJobResponse `json:"jobs"`
+	TotalCount int                   `json:"total_count"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"page_size"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+func toArchivedJobResponse(task *asynq.TaskInfo) ArchivedJobResponse {
+	payload := json.RawMessage(task.Payload)
+	if !json.Valid(payload) {
+		// Non-JSON payloads are returned as a JSON string rather than breaking the response
+		payload, _ = json.Marshal(string(task.Payload))
+	}
+	return ArchivedJobResponse{
+		ID:           task.ID,
+		Queue:        task.Queue,
+		Type:         task.Type,
+		Payload:      payload,
+		Retried:      task.Retried,
+		MaxRetry:     task.MaxRetry,
+		LastError:    task.LastErr,
+		LastFailedAt: task.LastFailedAt,
+	}
+}
+
+// ListArchived lists archived jobs, optionally filtered by ?queue= and ?type=,
+// most recently failed first
+func (c *JobAdminController) ListArchived(ctx *fiber.Ctx) error {
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.Query("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	queues, err := c.queues(ctx.Query("queue"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid queue",
+			"message": err.Error(),
+		})
+	}
+
+	jobType := ctx.Query("type")
+	var archived []ArchivedJobResponse
+	for _, queue := range queues {
+		tasks, err := c.archivedTasks(queue, jobType)
+		if err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to list archived jobs",
+				"message": err.Error(),
+			})
+		}
+		for _, task := range tasks {
+			archived = append(archived, toArchivedJobResponse(task))
+		}
+	}
+
+	sort.SliceStable(archived, func(i, j int) bool {
+		return archived[i].LastFailedAt.After(archived[j].LastFailedAt)
+	})
+
+	response := ArchivedJobListResponse{
+		Jobs:       []ArchivedJobResponse{},
+		TotalCount: len(archived),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (len(archived) + pageSize - 1) / pageSize,
+	}
+	if start := (page - 1) * pageSize; start < len(archived) {
+		response.Jobs = archived[start:min(start+pageSize, len(archived))]
+	}
+	return ctx.JSON(response)
+}
+
+// Requeue moves an archived job back to pending. Without ?queue= every queue the
+// server processes is searched for the job.
+func (c *JobAdminController) Requeue(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	queues, err := c.queues(ctx.Query("queue"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid queue",
+			"message": err.Error(),
+		})
+	}
+
+	for _, queue := range queues {
+		info, err := c.inspector.GetTaskInfo(queue, id)
+		if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+			continue
+		}
+		if err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to look up job",
+				"message": err.Error(),
+			})
+		}
+		if info.State != asynq.TaskStateArchived {
+			return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "Job is not archived",
+				"message": fmt.Sprintf("job %s is %s", id, info.State),
+			})
+		}
+
+		if err := c.inspector.RunTask(queue, id); err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to requeue job",
+				"message": err.Error(),
+			})
+		}
+		return ctx.JSON(fiber.Map{
+			"id":    id,
+			"queue": queue,
+			"type":  info.Type,
+		})
+	}
+
+	return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error":   "Job not found",
+		"message": fmt.Sprintf("no archived job %s", id),
+	})
+}
+
+// queues returns the requested queue, or every queue the server processes
+func (c *JobAdminController) queues(queue string) ([]string, error) {
+	if queue != "" {
+		if _, ok := jobs.ServerQueues[queue]; !ok {
+			return nil, fmt.Errorf("unknown queue '%s'", queue)
+		}
+		return []string{queue}, nil
+	}
+
+	queues := make([]string, 0, len(jobs.ServerQueues))
+	for name := range jobs.ServerQueues {
+		queues = append(queues, name)
+	}
+	sort.Strings(queues)
+	return queues, nil
+}
+
+// archivedTasks reads every archived task in queue, keeping those of jobType when
+// given. asynq bounds the archive size, so the scan stays small.
+func (c *JobAdminController) archivedTasks(queue, jobType string) ([]*asynq.TaskInfo, error) {
+	var matched []*asynq.TaskInfo
+	for page := 1; ; page++ {
+		tasks, err := c.inspector.ListArchivedTasks(queue, asynq.Page(page), asynq.PageSize(archivedScanPageSize))
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range tasks {
+			if jobType == "" || task.Type == jobType {
+				matched = append(matched, task)
+			}
+		}
+		if len(tasks) < archivedScanPageSize {
+			return matched, nil
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"example/modules/order/domain/entities"
 	"example/modules/order/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -19,19 +21,38 @@ func NewOrderController() *OrderController {
 	return &OrderController{}
 }
 
+// Routes are mounted under the /orders prefix
+func (c *OrderController) Routes() []xcomp.Route {
+	return []xcomp.Route{
+		{Method: fiber.MethodGet, Path: "/", Name: "orders.list", Handler: c.GetOrders},
+		{Method: fiber.MethodGet, Path: "/:id", Name: "orders.get", Handler: c.GetOrder},
+		{Method: fiber.MethodPost, Path: "/", Name: "orders.create", Handler: c.CreateOrder,
+			Middleware: []fiber.Handler{xcomp.ValidateBody[dto.CreateOrderRequest]()}},
+		{Method: fiber.MethodPut, Path: "/:id", Name: "orders.update", Handler: c.UpdateOrder,
+			Middleware: []fiber.Handler{xcomp.ValidateBody[dto.UpdateOrderRequest]()}},
+		{Method: fiber.MethodPatch, Path: "/:id/confirm", Name: "orders.confirm", Handler: c.ConfirmOrder},
+		{Method: fiber.MethodPatch, Path: "/:id/ship", Name: "orders.ship", Handler: c.ShipOrder},
+		{Method: fiber.MethodPatch, Path: "/:id/deliver", Name: "orders.deliver", Handler: c.DeliverOrder},
+		{Method: fiber.MethodPatch, Path: "/:id/cancel", Name: "orders.cancel", Handler: c.CancelOrder},
+		{Method: fiber.MethodPost, Path: "/:id/items", Name: "orders.items.add", Handler: c.AddOrderItem},
+		{Method: fiber.MethodPut, Path: "/:order_id/items/:product_id", Name: "orders.items.update_quantity", Handler: c.UpdateOrderItemQuantity},
+		{Method: fiber.MethodDelete, Path: "/:order_id/items/:product_id", Name: "orders.items.remove", Handler: c.RemoveOrderItem},
+		{Method: fiber.MethodDelete, Path: "/:id", Name: "orders.delete", Handler: c.DeleteOrder},
+	}
+}
+
+// CreateOrder expects the body already parsed and validated by ValidateBody
 func (c *OrderController) CreateOrder(ctx *fiber.Ctx) error {
-	var req dto.CreateOrderRequest
-	if err := ctx.BodyParser(&req); err != nil {
+	req := xcomp.ValidatedBody[dto.CreateOrderRequest](ctx)
+	if req == nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
-	order, err := c.OrderService.CreateOrder(ctx.Context(), req)
+	order, err := c.OrderService.CreateOrder(ctx.UserContext(), *req)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.Status(fiber.StatusCreated).JSON(order)
@@ -46,29 +67,21 @@ func (c *OrderController) GetOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.GetOrderByID(ctx.Context(), id)
+	order, err := c.OrderService.GetOrderByID(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Order not found",
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
 }
 
 func (c *OrderController) GetOrders(ctx *fiber.Ctx) error {
+	// The service clamps page and page_size into an xcomp.Page
 	page, _ := strconv.Atoi(ctx.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.Query("page_size", "10"))
 	customerIDParam := ctx.Query("customer_id")
 	statusParam := ctx.Query("status")
 
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
 	var orders *dto.OrderListResponse
 	var err error
 
@@ -79,18 +92,16 @@ func (c *OrderController) GetOrders(ctx *fiber.Ctx) error {
 				"error": "Invalid customer ID",
 			})
 		}
-		orders, err = c.OrderService.GetOrdersByCustomerID(ctx.Context(), customerID, int32(page), int32(pageSize))
+		orders, err = c.OrderService.GetOrdersByCustomerID(ctx.UserContext(), customerID, int32(page), int32(pageSize))
 	} else if statusParam != "" {
 		status := entities.OrderStatus(statusParam)
-		orders, err = c.OrderService.GetOrdersByStatus(ctx.Context(), status, int32(page), int32(pageSize))
+		orders, err = c.OrderService.GetOrdersByStatus(ctx.UserContext(), status, int32(page), int32(pageSize))
 	} else {
-		orders, err = c.OrderService.GetAllOrders(ctx.Context(), int32(page), int32(pageSize))
+		orders, err = c.OrderService.GetAllOrders(ctx.UserContext(), int32(page), int32(pageSize))
 	}
 
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(orders)
@@ -105,18 +116,16 @@ func (c *OrderController) UpdateOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	var req dto.UpdateOrderRequest
-	if err := ctx.BodyParser(&req); err != nil {
+	req := xcomp.ValidatedBody[dto.UpdateOrderRequest](ctx)
+	if req == nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
-	order, err := c.OrderService.UpdateOrder(ctx.Context(), id, req)
+	order, err := c.OrderService.UpdateOrder(ctx.UserContext(), id, *req)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -131,11 +140,9 @@ func (c *OrderController) ConfirmOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.ConfirmOrder(ctx.Context(), id)
+	order, err := c.OrderService.ConfirmOrder(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -150,11 +157,9 @@ func (c *OrderController) ShipOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.ShipOrder(ctx.Context(), id)
+	order, err := c.OrderService.ShipOrder(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -169,11 +174,9 @@ func (c *OrderController) DeliverOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.DeliverOrder(ctx.Context(), id)
+	order, err := c.OrderService.DeliverOrder(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -188,11 +191,9 @@ func (c *OrderController) CancelOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.CancelOrder(ctx.Context(), id)
+	order, err := c.OrderService.CancelOrder(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -208,17 +209,13 @@ func (c *OrderController) AddOrderItem(ctx *fiber.Ctx) error {
 	}
 
 	var req dto.AddOrderItemRequest
-	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := xcomp.BindAndValidate(ctx, &req); err != nil {
+		return xcomp.WriteBindError(ctx, err)
 	}
 
-	order, err := c.OrderService.AddOrderItem(ctx.Context(), id, req)
+	order, err := c.OrderService.AddOrderItem(ctx.UserContext(), id, req)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -242,17 +239,13 @@ func (c *OrderController) UpdateOrderItemQuantity(ctx *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateOrderItemQuantityRequest
-	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := xcomp.BindAndValidate(ctx, &req); err != nil {
+		return xcomp.WriteBindError(ctx, err)
 	}
 
-	order, err := c.OrderService.UpdateOrderItemQuantity(ctx.Context(), id, productID, req)
+	order, err := c.OrderService.UpdateOrderItemQuantity(ctx.UserContext(), id, productID, req)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -275,11 +268,9 @@ func (c *OrderController) RemoveOrderItem(ctx *fiber.Ctx) error {
 		})
 	}
 
-	order, err := c.OrderService.RemoveOrderItem(ctx.Context(), id, productID)
+	order, err := c.OrderService.RemoveOrderItem(ctx.UserContext(), id, productID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.JSON(order)
@@ -294,11 +285,9 @@ func (c *OrderController) DeleteOrder(ctx *fiber.Ctx) error {
 		})
 	}
 
-	err = c.OrderService.DeleteOrder(ctx.Context(), id)
+	err = c.OrderService.DeleteOrder(ctx.UserContext(), id)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.Status(fiber.StatusNoContent).Send(nil)
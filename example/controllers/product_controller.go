@@ -4,9 +4,10 @@ import (
 	"strconv"
 
 	"example/modules/product/application/dto"
-	"example/modules/product/domain/entities"
 	"example/modules/product/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -19,6 +20,19 @@ func (pc *ProductController) GetServiceName() string {
 	return "ProductController"
 }
 
+// Routes are mounted under the /products prefix
+func (pc *ProductController) Routes() []xcomp.Route {
+	return []xcomp.Route{
+		{Method: fiber.MethodGet, Path: "/", Name: "products.list", Handler: pc.ListProducts},
+		{Method: fiber.MethodGet, Path: "/search", Name: "products.search", Handler: pc.SearchProducts},
+		{Method: fiber.MethodGet, Path: "/:id", Name: "products.get", Handler: pc.GetProduct},
+		{Method: fiber.MethodPost, Path: "/", Name: "products.create", Handler: pc.CreateProduct},
+		{Method: fiber.MethodPut, Path: "/:id", Name: "products.update", Handler: pc.UpdateProduct},
+		{Method: fiber.MethodPatch, Path: "/:id/stock", Name: "products.update_stock", Handler: pc.UpdateProductStock},
+		{Method: fiber.MethodDelete, Path: "/:id", Name: "products.delete", Handler: pc.DeleteProduct},
+	}
+}
+
 func (pc *ProductController) GetProduct(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -29,18 +43,9 @@ func (pc *ProductController) GetProduct(c *fiber.Ctx) error {
 		})
 	}
 
-	product, err := pc.ProductService.GetProduct(c.Context(), id)
+	product, err := pc.ProductService.GetProduct(c.UserContext(), id)
 	if err != nil {
-		if err == entities.ErrProductNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Product not found",
-				"message": "The requested product does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -50,31 +55,22 @@ func (pc *ProductController) GetProduct(c *fiber.Ctx) error {
 }
 
 func (pc *ProductController) ListProducts(c *fiber.Ctx) error {
+	// The service clamps page and page_size into an xcomp.Page
 	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 32)
 	pageSize, _ := strconv.ParseInt(c.Query("page_size", "10"), 10, 32)
 	category := c.Query("category")
 
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
 	var products *dto.ProductListResponse
 	var err error
 
 	if category != "" {
-		products, err = pc.ProductService.ListProductsByCategory(c.Context(), category, int32(page), int32(pageSize))
+		products, err = pc.ProductService.ListProductsByCategory(c.UserContext(), category, int32(page), int32(pageSize))
 	} else {
-		products, err = pc.ProductService.ListProducts(c.Context(), int32(page), int32(pageSize))
+		products, err = pc.ProductService.ListProducts(c.UserContext(), int32(page), int32(pageSize))
 	}
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -97,16 +93,14 @@ func (pc *ProductController) SearchProducts(c *fiber.Ctx) error {
 
 	searchReq := &dto.ProductSearchRequest{
 		Query:    query,
+		Category: c.Query("category"),
 		Page:     int32(page),
 		PageSize: int32(pageSize),
 	}
 
-	products, err := pc.ProductService.SearchProducts(c.Context(), searchReq)
+	products, err := pc.ProductService.SearchProducts(c.UserContext(), searchReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Internal server error",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -117,19 +111,13 @@ func (pc *ProductController) SearchProducts(c *fiber.Ctx) error {
 
 func (pc *ProductController) CreateProduct(c *fiber.Ctx) error {
 	var req dto.CreateProductRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if err := xcomp.BindAndValidate(c, &req); err != nil {
+		return xcomp.WriteBindError(c, err)
 	}
 
-	product, err := pc.ProductService.CreateProduct(c.Context(), &req)
+	product, err := pc.ProductService.CreateProduct(c.UserContext(), &req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Failed to create product",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -149,25 +137,13 @@ func (pc *ProductController) UpdateProduct(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateProductRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if err := xcomp.BindAndValidate(c, &req); err != nil {
+		return xcomp.WriteBindError(c, err)
 	}
 
-	product, err := pc.ProductService.UpdateProduct(c.Context(), id, &req)
+	product, err := pc.ProductService.UpdateProduct(c.UserContext(), id, &req)
 	if err != nil {
-		if err == entities.ErrProductNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Product not found",
-				"message": "The requested product does not exist",
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Failed to update product",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -187,25 +163,13 @@ func (pc *ProductController) UpdateProductStock(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateStockRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid request body",
-			"message": err.Error(),
-		})
+	if err := xcomp.BindAndValidate(c, &req); err != nil {
+		return xcomp.WriteBindError(c, err)
 	}
 
-	product, err := pc.ProductService.UpdateProductStock(c.Context(), id, &req)
+	product, err := pc.ProductService.UpdateProductStock(c.UserContext(), id, &req)
 	if err != nil {
-		if err == entities.ErrProductNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Product not found",
-				"message": "The requested product does not exist",
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Failed to update product stock",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -224,18 +188,9 @@ func (pc *ProductController) DeleteProduct(c *fiber.Ctx) error {
 		})
 	}
 
-	err = pc.ProductService.DeleteProduct(c.Context(), id)
+	err = pc.ProductService.DeleteProduct(c.UserContext(), id)
 	if err != nil {
-		if err == entities.ErrProductNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "Product not found",
-				"message": "The requested product does not exist",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Failed to delete product",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
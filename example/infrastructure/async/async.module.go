@@ -11,6 +11,7 @@ import (
 	"xcomp"
 
 	"fmt"
+	"net/http"
 
 	"github.com/hibiken/asynq"
 	"github.com/hibiken/asynqmon"
@@ -29,11 +30,21 @@ func NewAsyncService(
 	redisClient *redis.Client,
 	orderService orderInterfaces.OrderService,
 	customerService interfaces.CustomerService,
+	config *xcomp.ConfigService,
 	logger xcomp.Logger,
-) *AsyncService {
-	redisAddr := redisClient.Options().Addr
+) (*AsyncService, error) {
+	queues, err := jobs.LoadQueues(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job queue config: %w", err)
+	}
 
-	scheduler := schedulers.NewCheckPendingOrderScheduler(redisAddr, logger)
+	redisOpt := asynq.RedisClientOpt{Addr: redisClient.Options().Addr}
+	scheduler := schedulers.NewCheckPendingOrderScheduler(
+		config,
+		jobs.NewEnqueuer(redisOpt, queues),
+		asynq.NewInspector(redisOpt),
+		logger,
+	)
 
 	processor := processors.NewCheckPendingOrderProcessor(
 		orderService,
@@ -41,17 +52,11 @@ func NewAsyncService(
 		logger,
 	)
 
-	redisOpt := asynq.RedisClientOpt{Addr: redisAddr}
-
 	server := asynq.NewServer(
 		redisOpt,
 		asynq.Config{
 			Concurrency: 10,
-			Queues: map[string]int{
-				"critical": 6,
-				"default":  3,
-				"low":      1,
-			},
+			Queues:      jobs.ServerQueues,
 		},
 	)
 
@@ -66,18 +71,18 @@ func NewAsyncService(
 		monitor:   monitor,
 		logger:    logger,
 		processor: processor,
-	}
+	}, nil
 }
 
 func (a *AsyncService) Start(ctx context.Context) error {
 	a.logger.Info("Starting async service")
 
-	mux := asynq.NewServeMux()
-	mux.HandleFunc(jobs.TypeCheckPendingOrder, a.processor.ProcessCheckPendingOrder)
+	registry := jobs.NewRegistry()
+	jobs.RegisterJob(registry, jobs.TypeCheckPendingOrder, a.processor.ProcessCheckPendingOrder)
 
 	go func() {
-		if err := a.server.Run(mux); err != nil {
-			a.logger.Error("Asynq server failed", xcomp.Field("error", err))
+		if err := a.server.Run(registry); err != nil {
+			a.logger.Error("Asynq server failed", xcomp.Err(err))
 		}
 	}()
 
@@ -103,59 +108,89 @@ func (a *AsyncService) Stop() {
 	a.logger.Info("Async service stopped")
 }
 
+// HealthCheck reports the scheduler's health, see Container.HealthCheck
+func (a *AsyncService) HealthCheck(ctx context.Context) error {
+	return a.scheduler.HealthCheck(ctx)
+}
+
+func (a *AsyncService) GetScheduler() *schedulers.CheckPendingOrderScheduler {
+	return a.scheduler
+}
+
 func (a *AsyncService) GetMonitorHandler() *asynqmon.HTTPHandler {
 	return a.monitor
 }
 
+// serveMonitor exposes the asynq monitoring UI on its own port
+func (a *AsyncService) serveMonitor(port int) {
+	go func() {
+		a.logger.Info("Asynq monitor starting",
+			xcomp.Field("port", port),
+			xcomp.Field("path", "/monitoring"))
+
+		if err := http.ListenAndServe(fmt.Sprintf(":%d", port), a.monitor); err != nil {
+			a.logger.Error("Asynq monitor failed to start",
+				xcomp.Field("port", port),
+				xcomp.Err(err))
+		}
+	}()
+}
+
+// redisAvailable reports whether background jobs can run: they are queued in Redis
+func redisAvailable(c *xcomp.Container) bool {
+	redisClient, _ := c.Get("RedisClient").(*redis.Client)
+	return redisClient != nil
+}
+
 func CreateAsyncModule() xcomp.Module {
 	return xcomp.NewModule().
+		Named("async").
 		AddFactory("AsyncService", func(c *xcomp.Container) any {
-			redisClient, ok := c.Get("RedisClient").(*redis.Client)
-			if !ok || redisClient == nil {
-				panic("RedisClient not found or invalid type in container")
+			// A disabled Redis is registered as a typed nil client
+			redisClient := xcomp.MustResolve[*redis.Client](c, "RedisClient")
+			if redisClient == nil {
+				panic("service 'RedisClient' is disabled")
 			}
 
-			logger, ok := c.Get("Logger").(xcomp.Logger)
-			if !ok || logger == nil {
-				panic("Logger not found or invalid type in container")
-			}
+			logger := xcomp.MustResolve[xcomp.Logger](c, "Logger")
+			orderService := xcomp.MustResolve[orderInterfaces.OrderService](c, "OrderService")
+			customerService := xcomp.MustResolve[interfaces.CustomerService](c, "CustomerService")
+			config := xcomp.MustResolve[*xcomp.ConfigService](c, "ConfigService")
 
-			// Debug: List all available services
-			services := c.ListServices()
-			logger.Info("Available services in container for AsyncService creation",
-				xcomp.Field("services", services))
+			logger.Info("Creating AsyncService with dependencies",
+				xcomp.Field("redisAddr", redisClient.Options().Addr))
 
-			// Get OrderService from container (from orderModule)
-			orderServiceRaw := c.Get("OrderService")
-			if orderServiceRaw == nil {
-				logger.Error("OrderService is nil in container")
-				panic("OrderService not found in container")
+			asyncService, err := NewAsyncService(redisClient, orderService, customerService, config, logger)
+			if err != nil {
+				panic(err)
 			}
-
-			orderService, ok := orderServiceRaw.(orderInterfaces.OrderService)
-			if !ok {
-				logger.Error("OrderService has wrong type",
-					xcomp.Field("actual_type", fmt.Sprintf("%T", orderServiceRaw)))
-				panic("OrderService has invalid type in container")
+			return asyncService
+		}).
+		OnStart(func(ctx context.Context, c *xcomp.Container) error {
+			if !redisAvailable(c) {
+				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
+					logger.Warn("Redis unavailable, background jobs are disabled")
+				}
+				return nil
 			}
 
-			logger.Info("Successfully retrieved OrderService from container",
-				xcomp.Field("orderService_type", fmt.Sprintf("%T", orderService)),
-				xcomp.Field("orderService_pointer", fmt.Sprintf("%p", orderService)))
-
-			// Debug: Check if we can access the concrete type to inspect its fields
-			logger.Info("Investigating OrderService dependency injection status")
-
-			customerService, ok := c.Get("CustomerService").(interfaces.CustomerService)
-			if !ok || customerService == nil {
-				panic("CustomerService not found or invalid type in container")
+			asyncService := xcomp.MustResolve[*AsyncService](c, "AsyncService")
+			if metrics, ok := c.Get("Metrics").(*xcomp.Metrics); ok {
+				asyncService.GetScheduler().SetMetrics(metrics)
+			}
+			if err := asyncService.Start(ctx); err != nil {
+				return err
 			}
 
-			logger.Info("Creating AsyncService with dependencies",
-				xcomp.Field("redisAddr", redisClient.Options().Addr))
-
-			asyncService := NewAsyncService(redisClient, orderService, customerService, logger)
-			return asyncService
+			config, _ := c.Get("ConfigService").(*xcomp.ConfigService)
+			asyncService.serveMonitor(config.GetInt("async.monitor.port", 8080))
+			return nil
+		}).
+		OnStop(func(ctx context.Context, c *xcomp.Container) error {
+			if redisAvailable(c) {
+				xcomp.MustResolve[*AsyncService](c, "AsyncService").Stop()
+			}
+			return nil
 		}).
 		Build()
 }
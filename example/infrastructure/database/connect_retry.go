@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcomp"
+)
+
+const maxConnectRetryDelay = 5 * time.Second
+
+// connectRetry is how often and how patiently a dependency is dialed at startup
+type connectRetry struct {
+	Retries int
+	Delay   time.Duration
+}
+
+// loadConnectRetry reads <prefix>.connect_retries and <prefix>.connect_retry_delay_ms
+func loadConnectRetry(config *xcomp.ConfigService, prefix string, defaultRetries int) connectRetry {
+	retries := config.GetInt(prefix+".connect_retries", defaultRetries)
+	if retries < 0 {
+		retries = 0
+	}
+	delay := time.Duration(config.GetInt(prefix+".connect_retry_delay_ms", 500)) * time.Millisecond
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	return connectRetry{Retries: retries, Delay: delay}
+}
+
+// connectWithRetry calls connect until it succeeds, the retries are used up or ctx
+// expires, doubling the delay between attempts up to maxConnectRetryDelay. In
+// container deployments the database often starts a moment after the app.
+func connectWithRetry(ctx context.Context, logger xcomp.Logger, name string, retry connectRetry, connect func(context.Context) error) error {
+	delay := retry.Delay
+	attempts := retry.Retries + 1
+
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 && logger != nil {
+				logger.Info("Connected after retrying", xcomp.String("dependency", name), xcomp.Int("attempt", attempt))
+			}
+			return nil
+		}
+
+		if attempt >= attempts {
+			return fmt.Errorf("%s unavailable after %d attempts: %w", name, attempt, err)
+		}
+
+		if logger != nil {
+			logger.Warn("Connection attempt failed, retrying",
+				xcomp.String("dependency", name),
+				xcomp.Int("attempt", attempt),
+				xcomp.Int("max_attempts", attempts),
+				xcomp.Duration("retry_in", delay),
+				xcomp.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s unavailable, gave up after %d attempts: %w", name, attempt, err)
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxConnectRetryDelay)
+	}
+}
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FindOrCreate runs an idempotent insert and falls back to a lookup when it conflicts.
+// insert should be an INSERT ... ON CONFLICT DO NOTHING RETURNING query, which yields
+// pgx.ErrNoRows when the row already exists; find then fetches the existing row.
+// Uniqueness is enforced by the database, so concurrent callers can't both insert.
+// created reports whether this call inserted the row.
+func FindOrCreate[T any](ctx context.Context, insert func(context.Context) (T, error), find func(context.Context) (T, error)) (result T, created bool, err error) {
+	result, err = insert(ctx)
+	if err == nil {
+		return result, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return result, false, err
+	}
+
+	result, err = find(ctx)
+	return result, false, err
+}
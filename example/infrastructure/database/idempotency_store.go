@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"xcomp"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisIdempotencyStore backs xcomp's idempotency middleware with Redis.
+// Without a Redis client every request is processed normally.
+type RedisIdempotencyStore struct {
+	RedisClient *redis.Client `inject:"RedisClient"`
+}
+
+func (s *RedisIdempotencyStore) GetServiceName() string {
+	return "IdempotencyStore"
+}
+
+func (s *RedisIdempotencyStore) Get(ctx context.Context, key string) (*xcomp.IdempotentResponse, error) {
+	if s.RedisClient == nil {
+		return nil, nil
+	}
+
+	val, err := s.RedisClient.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get idempotent response: %w", err)
+	}
+
+	var response xcomp.IdempotentResponse
+	if err := json.Unmarshal([]byte(val), &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotent response: %w", err)
+	}
+
+	return &response, nil
+}
+
+func (s *RedisIdempotencyStore) Set(ctx context.Context, key string, response *xcomp.IdempotentResponse, ttl time.Duration) error {
+	if s.RedisClient == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+
+	if err := s.RedisClient.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+func (s *RedisIdempotencyStore) Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	if s.RedisClient == nil {
+		return true, nil
+	}
+
+	acquired, err := s.RedisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire idempotency lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// unlockScript deletes the lock only if it still holds the caller's token, in one
+// round trip so the check and the delete can't interleave with another request
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (s *RedisIdempotencyStore) Unlock(ctx context.Context, key, token string) error {
+	if s.RedisClient == nil {
+		return nil
+	}
+
+	if err := unlockScript.Run(ctx, s.RedisClient, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency lock: %w", err)
+	}
+	return nil
+}
+
+var _ xcomp.IdempotencyStore = (*RedisIdempotencyStore)(nil)
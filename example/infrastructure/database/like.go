@@ -0,0 +1,11 @@
+package database
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike makes user input match literally inside a LIKE/ILIKE pattern; without
+// it a search for "%" matches every row. Backslash is Postgres' default escape.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"xcomp"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SlowQueryTracer logs queries slower than Threshold, tagged with the caller's request_id
+type SlowQueryTracer struct {
+	Logger    xcomp.Logger
+	Threshold time.Duration
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+}
+
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{sql: data.SQL, start: time.Now()})
+}
+
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok || t.Logger == nil {
+		return
+	}
+
+	duration := time.Since(trace.start)
+	if data.Err != nil {
+		t.Logger.Debug("Query failed",
+			xcomp.RequestIDField(ctx),
+			xcomp.Field("sql", trace.sql),
+			xcomp.Field("duration", duration.String()),
+			xcomp.Err(data.Err))
+		return
+	}
+
+	if duration >= t.Threshold {
+		t.Logger.Warn("Slow query",
+			xcomp.RequestIDField(ctx),
+			xcomp.Field("sql", trace.sql),
+			xcomp.Field("duration", duration.String()),
+			xcomp.Field("rows_affected", data.CommandTag.RowsAffected()))
+	}
+}
+
+var _ pgx.QueryTracer = (*SlowQueryTracer)(nil)
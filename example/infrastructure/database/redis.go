@@ -1,6 +1,14 @@
 package database
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
 	"xcomp"
 
 	"github.com/redis/go-redis/v9"
@@ -8,26 +16,120 @@ import (
 
 type RedisService struct {
 	Config *xcomp.ConfigService `inject:"ConfigService"`
-	client *redis.Client
+	Logger xcomp.Logger         `inject:"Logger"`
+	// NoRetry fails on the first unsuccessful connect, for probes that must answer quickly
+	NoRetry bool
+	client  *redis.Client
 }
 
 func (rs *RedisService) GetServiceName() string {
 	return "RedisClient"
 }
 
+// GetClient returns nil until Initialize succeeds
 func (rs *RedisService) GetClient() *redis.Client {
 	return rs.client
 }
 
-func (rs *RedisService) Initialize() error {
-	redisURL := rs.Config.GetString("redis.url", "redis://localhost:6379/0")
+// ErrInvalidRedisConfig marks Initialize failures caused by the config rather
+// than an unreachable server
+var ErrInvalidRedisConfig = errors.New("invalid redis config")
+
+// RedisConfig is the redis section of the config; zero pool and timeout
+// values keep the go-redis defaults
+type RedisConfig struct {
+	URL          string        `yaml:"url"`
+	PoolSize     int           `yaml:"pool_size"`
+	MinIdleConns int           `yaml:"min_idle_conns"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	TLS          struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"tls"`
+	// ConnectTimeout covers every connect attempt
+	ConnectTimeout time.Duration `yaml:"connect_timeout_seconds"`
+}
+
+func defaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		URL:            "redis://localhost:6379/0",
+		ConnectTimeout: 10 * time.Second,
+	}
+}
+
+// Options builds the client options from the URL and the pool settings
+func (c RedisConfig) Options() (*redis.Options, error) {
+	redisURL := strings.TrimSpace(c.URL)
+	if redisURL == "" {
+		return nil, fmt.Errorf("%w: redis.url is empty", ErrInvalidRedisConfig)
+	}
+	if c.PoolSize < 0 || c.MinIdleConns < 0 {
+		return nil, fmt.Errorf("%w: redis.pool_size and redis.min_idle_conns must not be negative", ErrInvalidRedisConfig)
+	}
+	if c.PoolSize > 0 && c.MinIdleConns > c.PoolSize {
+		return nil, fmt.Errorf("%w: redis.min_idle_conns (%d) exceeds redis.pool_size (%d)",
+			ErrInvalidRedisConfig, c.MinIdleConns, c.PoolSize)
+	}
 
 	options, err := redis.ParseURL(redisURL)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRedisConfig, err)
+	}
+
+	if c.PoolSize > 0 {
+		options.PoolSize = c.PoolSize
+	}
+	options.MinIdleConns = c.MinIdleConns
+	if c.DialTimeout > 0 {
+		options.DialTimeout = c.DialTimeout
+	}
+	if c.ReadTimeout > 0 {
+		options.ReadTimeout = c.ReadTimeout
+	}
+	// rediss:// URLs already carry a TLS config
+	if c.TLS.Enabled && options.TLSConfig == nil {
+		host, _, _ := net.SplitHostPort(options.Addr)
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, ServerName: host}
+	}
+	return options, nil
+}
+
+// Initialize connects and pings Redis, returning an error when the ping fails.
+// Config problems wrap ErrInvalidRedisConfig; an unreachable server does not.
+func (rs *RedisService) Initialize() error {
+	redisConfig := defaultRedisConfig()
+	if err := rs.Config.Unmarshal("redis", &redisConfig); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRedisConfig, err)
+	}
+
+	options, err := redisConfig.Options()
+	if err != nil {
+		return fmt.Errorf("failed to parse redis config: %w", err)
+	}
+
+	client := redis.NewClient(options)
+
+	// Redis gets fewer retries and a shorter deadline than the database
+	ctx, cancel := context.WithTimeout(context.Background(), redisConfig.ConnectTimeout)
+	defer cancel()
+
+	retry := loadConnectRetry(rs.Config, "redis", 3)
+	if rs.NoRetry {
+		retry.Retries = 0
+	}
+	ping := func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+	if err := connectWithRetry(ctx, rs.Logger, "redis", retry, ping); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to ping redis at %s: %w", options.Addr, err)
 	}
 
-	rs.client = redis.NewClient(options)
+	rs.client = client
+	rs.Logger.Info("Redis connection initialized",
+		xcomp.Field("addr", options.Addr),
+		xcomp.Field("pool_size", options.PoolSize),
+		xcomp.Field("tls", options.TLSConfig != nil))
 
 	return nil
 }
@@ -0,0 +1,88 @@
+package database_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example/infrastructure/database"
+
+	"xcomp"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newRedisService(t *testing.T, url string, settings ...string) *database.RedisService {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "redis:\n  url: " + url + "\n  connect_timeout_seconds: 1s\n  connect_retries: 0\n"
+	for _, setting := range settings {
+		content += "  " + setting + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return &database.RedisService{
+		Config:  xcomp.NewConfigService(path),
+		Logger:  xcomp.NewDevelopmentLogger(),
+		NoRetry: true,
+	}
+}
+
+func TestRedisServiceInitializePingsServer(t *testing.T) {
+	server := miniredis.RunT(t)
+	rs := newRedisService(t, "redis://"+server.Addr()+"/0")
+
+	if err := rs.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { rs.Close() })
+	if rs.GetClient() == nil {
+		t.Fatal("GetClient is nil after a successful Initialize")
+	}
+}
+
+func TestRedisServiceInitializeFailsOnBadAddress(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	// Nothing listens on the address once the server is gone
+	server.Close()
+	rs := newRedisService(t, "redis://"+addr+"/0")
+
+	err := rs.Initialize()
+	if err == nil {
+		rs.Close()
+		t.Fatal("Initialize succeeded against an address nothing listens on")
+	}
+	if errors.Is(err, database.ErrInvalidRedisConfig) {
+		t.Fatalf("Initialize = %v, want a ping failure rather than a config error", err)
+	}
+	if rs.GetClient() != nil {
+		t.Fatal("GetClient is set after a failed Initialize")
+	}
+}
+
+// The port travels in redis.url, so the numeric settings a string can reach are
+// the pool sizes: environment overrides always supply them as strings
+func TestRedisServiceInitializeAcceptsStringSettings(t *testing.T) {
+	server := miniredis.RunT(t)
+	t.Setenv("REDIS__POOL_SIZE", "20")
+	rs := newRedisService(t, "redis://"+server.Addr()+"/0", "pool_size: 5", `min_idle_conns: "2"`)
+
+	if err := rs.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { rs.Close() })
+	if options := rs.GetClient().Options(); options.PoolSize != 20 || options.MinIdleConns != 2 {
+		t.Fatalf("pool_size = %d, min_idle_conns = %d; want 20 and 2", options.PoolSize, options.MinIdleConns)
+	}
+}
+
+func TestRedisServiceInitializeRejectsNonNumericPort(t *testing.T) {
+	rs := newRedisService(t, "redis://localhost:port/0")
+
+	if err := rs.Initialize(); !errors.Is(err, database.ErrInvalidRedisConfig) {
+		t.Fatalf("Initialize = %v, want %v", err, database.ErrInvalidRedisConfig)
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txContextKey struct{}
+
+// ContextWithTx returns a context carrying tx; repositories pick it up
+// through TxFromContext so their queries join the transaction.
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction started by TxManager.WithTx, if any
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+	return tx, ok && tx != nil
+}
+
+// TxManager runs a unit of work in one database transaction
+type TxManager struct {
+	DB *pgxpool.Pool `inject:"DatabaseConnection"`
+}
+
+func (m *TxManager) GetServiceName() string {
+	return "TxManager"
+}
+
+// WithTx begins a transaction, passes fn a context carrying it, and commits
+// when fn succeeds. The transaction is rolled back if fn returns an error or
+// panics. Nested calls reuse the outer transaction.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	if m.DB == nil {
+		return errors.New("transaction manager has no database connection")
+	}
+
+	tx, err := m.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+
+	if err := fn(ContextWithTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
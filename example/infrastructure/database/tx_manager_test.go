@@ -0,0 +1,108 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"example/infrastructure/database"
+	"example/modules/order/domain/entities"
+	"example/modules/order/domain/interfaces"
+	"example/testenv"
+
+	"xcomp"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestWithTxRollsBackOrderWhenItemInsertFails(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+
+	txManager := xcomp.MustResolve[*database.TxManager](env.Container, "TxManager")
+	orders := xcomp.MustResolve[interfaces.OrderRepository](env.Container, "OrderRepository")
+	items := xcomp.MustResolve[interfaces.OrderItemRepository](env.Container, "OrderItemRepository")
+
+	order := entities.NewOrder(uuid.New())
+	err := txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := orders.Create(ctx, order); err != nil {
+			t.Fatalf("order insert failed inside the transaction: %v", err)
+		}
+		// Breaks the order_items_quantity_positive check
+		item := entities.NewOrderItem(order.ID, uuid.New(), "Keyboard", 1, 10)
+		item.Quantity = 0
+		return items.Create(ctx, item)
+	})
+	if err == nil {
+		t.Fatal("WithTx succeeded although the item insert violates a check constraint")
+	}
+
+	var count int
+	if err := env.DB.QueryRow(ctx, "SELECT count(*) FROM orders WHERE id = $1", order.ID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("%d order rows remain after the rollback, want 0", count)
+	}
+}
+
+func TestWithTxCommitsOrderAndItems(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+
+	txManager := xcomp.MustResolve[*database.TxManager](env.Container, "TxManager")
+	orders := xcomp.MustResolve[interfaces.OrderRepository](env.Container, "OrderRepository")
+	items := xcomp.MustResolve[interfaces.OrderItemRepository](env.Container, "OrderItemRepository")
+
+	order := entities.NewOrder(uuid.New())
+	err := txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := orders.Create(ctx, order); err != nil {
+			return err
+		}
+		return items.Create(ctx, entities.NewOrderItem(order.ID, uuid.New(), "Keyboard", 2, 10))
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	stored, err := items.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("%d items committed, want 1", len(stored))
+	}
+}
+
+// fakeTx stands in for a transaction already carried by the context
+type fakeTx struct {
+	pgx.Tx
+}
+
+func TestWithTxReusesOuterTransaction(t *testing.T) {
+	outer := &fakeTx{}
+	ctx := database.ContextWithTx(context.Background(), outer)
+
+	// No pool: a nested call must not try to begin a transaction of its own
+	txManager := &database.TxManager{}
+	err := txManager.WithTx(ctx, func(ctx context.Context) error {
+		if tx, ok := database.TxFromContext(ctx); !ok || tx != outer {
+			t.Fatal("nested WithTx did not pass the outer transaction on")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+}
+
+func TestWithTxWithoutDatabase(t *testing.T) {
+	called := false
+	err := (&database.TxManager{}).WithTx(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Fatalf("WithTx without a pool = %v (fn called: %v), want an error before fn runs", err, called)
+	}
+}
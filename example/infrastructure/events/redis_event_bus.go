@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"xcomp"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const channelPrefix = "events:"
+
+// RedisEventBus publishes events on Redis pub/sub so every instance running Start
+// receives them. Handlers run asynchronously in the subscriber loop; their errors are
+// logged because the publisher has already returned.
+type RedisEventBus struct {
+	RedisClient *redis.Client `inject:"RedisClient"`
+	Logger      xcomp.Logger  `inject:"Logger"`
+
+	handlers xcomp.EventHandlers
+}
+
+func (b *RedisEventBus) GetServiceName() string {
+	return "EventBus"
+}
+
+func (b *RedisEventBus) Publish(ctx context.Context, topic string, payload any) error {
+	event, err := xcomp.NewEvent(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", topic, err)
+	}
+
+	if err := b.RedisClient.Publish(ctx, channelPrefix+topic, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", topic, err)
+	}
+	return nil
+}
+
+func (b *RedisEventBus) Subscribe(topic string, handler xcomp.EventHandler) {
+	b.handlers.Add(topic, handler)
+}
+
+// Start receives events until ctx is cancelled. Events published while no instance
+// is running are not delivered, as with any Redis pub/sub channel.
+func (b *RedisEventBus) Start(ctx context.Context) {
+	pubsub := b.RedisClient.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			b.dispatch(ctx, message.Payload)
+		}
+	}
+}
+
+func (b *RedisEventBus) dispatch(ctx context.Context, data string) {
+	var event xcomp.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		b.Logger.Warn("Dropping malformed event", xcomp.Err(err))
+		return
+	}
+
+	if err := b.handlers.Dispatch(ctx, event); err != nil {
+		b.Logger.Error("Event handler failed",
+			xcomp.String("topic", event.Topic),
+			xcomp.Err(err))
+	}
+}
+
+var _ xcomp.EventBus = (*RedisEventBus)(nil)
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"xcomp"
+
+	"github.com/hibiken/asynq"
+)
+
+const DefaultQueue = "default"
+
+// ServerQueues are the queues the async server processes, with their priorities
+var ServerQueues = map[string]int{
+	"critical": 6,
+	"default":  3,
+	"low":      1,
+}
+
+// Queues routes each job type to a queue. Job types without an entry in
+// async.job_queues go to async.default_queue.
+type Queues struct {
+	defaultQueue string
+	byType       map[string]string
+}
+
+// LoadQueues reads the job type to queue mapping and checks that every queue it
+// names is one the server processes, so a typo can't strand jobs in a dead queue
+func LoadQueues(config *xcomp.ConfigService) (*Queues, error) {
+	queues := &Queues{
+		defaultQueue: config.GetString("async.default_queue", DefaultQueue),
+		byType:       make(map[string]string),
+	}
+	if err := validateQueue("async.default_queue", queues.defaultQueue); err != nil {
+		return nil, err
+	}
+
+	mapping, _ := config.Get("async.job_queues").(map[string]any)
+	for jobType, queue := range mapping {
+		name, ok := queue.(string)
+		if !ok {
+			return nil, fmt.Errorf("async.job_queues.%s must be a queue name, got %T", jobType, queue)
+		}
+		if err := validateQueue("async.job_queues."+jobType, name); err != nil {
+			return nil, err
+		}
+		queues.byType[jobType] = name
+	}
+	return queues, nil
+}
+
+func validateQueue(key, queue string) error {
+	if _, ok := ServerQueues[queue]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(ServerQueues))
+	for name := range ServerQueues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%s: unknown queue '%s', expected one of %s", key, queue, strings.Join(names, ", "))
+}
+
+// For returns the queue for jobType
+func (q *Queues) For(jobType string) string {
+	if queue, ok := q.byType[jobType]; ok {
+		return queue
+	}
+	return q.defaultQueue
+}
+
+// Enqueuer enqueues tasks on the queue configured for their type
+type Enqueuer struct {
+	client *asynq.Client
+	queues *Queues
+}
+
+func NewEnqueuer(redisOpt asynq.RedisConnOpt, queues *Queues) *Enqueuer {
+	return &Enqueuer{
+		client: asynq.NewClient(redisOpt),
+		queues: queues,
+	}
+}
+
+// Enqueue places task on its configured queue; an asynq.Queue option in opts
+// still takes precedence
+func (e *Enqueuer) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	opts = append([]asynq.Option{asynq.Queue(e.queues.For(task.Type()))}, opts...)
+	return e.client.Enqueue(task, opts...)
+}
+
+// QueueFor returns the queue tasks of taskType are placed on
+func (e *Enqueuer) QueueFor(taskType string) string {
+	return e.queues.For(taskType)
+}
+
+func (e *Enqueuer) Close() error {
+	return e.client.Close()
+}
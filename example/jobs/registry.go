@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hibiken/asynq"
+)
+
+// Registry routes tasks to typed handlers; register jobs with RegisterJob and
+// pass the registry to asynq.Server.Run
+type Registry struct {
+	mux   *asynq.ServeMux
+	types []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{mux: asynq.NewServeMux()}
+}
+
+// RegisterJob routes tasks of typeName to handler, decoding their JSON
+// payload into T. A payload that doesn't decode is not retried.
+func RegisterJob[T any](r *Registry, typeName string, handler func(ctx context.Context, job T) error) {
+	r.mux.HandleFunc(typeName, func(ctx context.Context, t *asynq.Task) error {
+		job, err := DecodePayload[T](t)
+		if err != nil {
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+		return handler(ctx, job)
+	})
+	r.types = append(r.types, typeName)
+}
+
+// ProcessTask implements asynq.Handler
+func (r *Registry) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	return r.mux.ProcessTask(ctx, t)
+}
+
+// Types returns the registered job types in sorted order
+func (r *Registry) Types() []string {
+	types := append([]string(nil), r.types...)
+	sort.Strings(types)
+	return types
+}
+
+// NewTask marshals job as the JSON payload of a typeName task
+func NewTask[T any](typeName string, job T, opts ...asynq.Option) (*asynq.Task, error) {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", typeName, err)
+	}
+	return asynq.NewTask(typeName, payload, opts...), nil
+}
+
+// DecodePayload unmarshals the JSON payload of t into T
+func DecodePayload[T any](t *asynq.Task) (T, error) {
+	var job T
+	if err := json.Unmarshal(t.Payload(), &job); err != nil {
+		return job, fmt.Errorf("failed to unmarshal %s payload: %w", t.Type(), err)
+	}
+	return job, nil
+}
+
+// Enqueue marshals job and places it on the queue configured for typeName
+func Enqueue[T any](e *Enqueuer, typeName string, job T, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	task, err := NewTask(typeName, job)
+	if err != nil {
+		return nil, err
+	}
+	return e.Enqueue(task, opts...)
+}
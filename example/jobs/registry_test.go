@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+)
+
+func TestEnqueuedPayloadDecodesToSameJob(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisOpt := asynq.RedisClientOpt{Addr: server.Addr()}
+	enqueuer := NewEnqueuer(redisOpt, &Queues{defaultQueue: DefaultQueue})
+	defer enqueuer.Close()
+
+	job := CheckPendingOrderJob{CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)}
+	info, err := Enqueue(enqueuer, TypeCheckPendingOrder, job)
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	inspector := asynq.NewInspector(redisOpt)
+	defer inspector.Close()
+	stored, err := inspector.GetTaskInfo(info.Queue, info.ID)
+	if err != nil {
+		t.Fatalf("GetTaskInfo: %v", err)
+	}
+
+	decoded, err := DecodePayload[CheckPendingOrderJob](asynq.NewTask(stored.Type, stored.Payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.CreatedAt.Equal(job.CreatedAt) {
+		t.Fatalf("decoded job = %+v, want %+v", decoded, job)
+	}
+}
+
+func TestRegistryPassesDecodedJobToHandler(t *testing.T) {
+	registry := NewRegistry()
+	var received CheckPendingOrderJob
+	RegisterJob(registry, TypeCheckPendingOrder, func(ctx context.Context, job CheckPendingOrderJob) error {
+		received = job
+		return nil
+	})
+
+	job := CheckPendingOrderJob{CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)}
+	task, err := NewTask(TypeCheckPendingOrder, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.ProcessTask(context.Background(), task); err != nil {
+		t.Fatalf("ProcessTask: %v", err)
+	}
+	if !received.CreatedAt.Equal(job.CreatedAt) {
+		t.Fatalf("handler received %+v, want %+v", received, job)
+	}
+}
+
+func TestRegistrySkipsRetryForUndecodablePayload(t *testing.T) {
+	registry := NewRegistry()
+	RegisterJob(registry, TypeCheckPendingOrder, func(ctx context.Context, job CheckPendingOrderJob) error {
+		t.Fatal("handler ran for a payload that does not decode")
+		return nil
+	})
+
+	err := registry.ProcessTask(context.Background(), asynq.NewTask(TypeCheckPendingOrder, []byte("{")))
+	if !errors.Is(err, asynq.SkipRetry) {
+		t.Fatalf("ProcessTask = %v, want %v", err, asynq.SkipRetry)
+	}
+}
@@ -1,9 +1,6 @@
 package jobs
 
-import (
-	"encoding/json"
-	"time"
-)
+import "time"
 
 const (
 	TypeCheckPendingOrder = "check_pending_order"
@@ -18,7 +15,3 @@ func NewCheckPendingOrderJob() *CheckPendingOrderJob {
 		CreatedAt: time.Now(),
 	}
 }
-
-func (j *CheckPendingOrderJob) Payload() ([]byte, error) {
-	return json.Marshal(j)
-}
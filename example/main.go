@@ -2,25 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"example/infrastructure/async"
 	"example/infrastructure/database"
+	"example/infrastructure/events"
 	"example/modules/customer"
-	customerInterfaces "example/modules/customer/domain/interfaces"
 	"example/modules/order"
-	orderInterfaces "example/modules/order/domain/interfaces"
 	"example/modules/product"
 
 	"xcomp"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
@@ -34,16 +35,31 @@ var (
 	GitCommit = "unknown"
 )
 
-func createInfrastructureModule(container *xcomp.Container) xcomp.Module {
-	return xcomp.NewModule().
-		AddFactory("ConfigService", func(container *xcomp.Container) any {
-			configFile := os.Getenv("CONFIG_FILE")
-			if configFile == "" {
-				configFile = "config-dev.yaml"
-			}
+func configFilePath() string {
+	if configFile := os.Getenv("CONFIG_FILE"); configFile != "" {
+		return configFile
+	}
+	return "config-dev.yaml"
+}
 
-			return xcomp.NewConfigService(configFile)
-		}).
+// redisEnabled is the provider condition for Redis-backed implementations
+func redisEnabled(container *xcomp.Container) bool {
+	configService, _ := container.Get("ConfigService").(*xcomp.ConfigService)
+	return configService == nil || configService.GetBool("redis.enabled", true)
+}
+
+// redisEventsEnabled selects Redis pub/sub for the EventBus; without Redis or the
+// async subsystem events are delivered in-process instead of being dropped
+func redisEventsEnabled(container *xcomp.Container) bool {
+	redisClient, _ := container.Get("RedisClient").(*redis.Client)
+	return redisClient != nil && container.ModuleEnabled("async")
+}
+
+// createInfrastructureModule provides configService as "ConfigService", so the
+// container sees the same config the caller validated and filtered modules with
+func createInfrastructureModule(configService *xcomp.ConfigService) xcomp.Module {
+	return xcomp.NewModule().
+		AddService("ConfigService", configService).
 		AddFactory("Logger", func(container *xcomp.Container) any {
 			configService, _ := container.Get("ConfigService").(*xcomp.ConfigService)
 			if configService != nil {
@@ -52,124 +68,187 @@ func createInfrastructureModule(container *xcomp.Container) xcomp.Module {
 			return xcomp.NewDevelopmentLogger()
 		}).
 		AddFactory("RedisClient", func(container *xcomp.Container) any {
+			// Redis can be switched off: without it caches become no-ops and background jobs are disabled
+			logger, _ := container.Get("Logger").(xcomp.Logger)
+			if !redisEnabled(container) {
+				if logger != nil {
+					logger.Info("Redis disabled by configuration")
+				}
+				return (*redis.Client)(nil)
+			}
+
+			// Inject connects through RedisService.Initialize
 			redisService := &database.RedisService{}
-			container.Inject(redisService)
-			redisService.Initialize()
+			if err := container.Inject(redisService); err != nil {
+				var initErr *xcomp.InitializeError
+				if !errors.As(err, &initErr) {
+					panic("Failed to inject RedisService dependencies: " + err.Error())
+				}
+				// Once enabled, a broken config and an unreachable server both fail startup
+				panic(fmt.Errorf("failed to set up redis: %w", err))
+			}
 			return redisService.GetClient()
 		}).
-		AddFactory("DatabaseConnection", func(container *xcomp.Container) any {
+		AddFactoryIf("IdempotencyStore", redisEnabled, func(container *xcomp.Container) any {
+			store := &database.RedisIdempotencyStore{}
+			if err := container.Inject(store); err != nil {
+				panic("Failed to inject IdempotencyStore dependencies: " + err.Error())
+			}
+			return store
+		}).
+		AddFactory("IdempotencyStore", func(container *xcomp.Container) any {
+			// Without Redis, duplicates are still caught within this instance
+			return xcomp.NewMemoryIdempotencyStore()
+		}).
+		AddFactoryIf("EventBus", redisEventsEnabled, func(container *xcomp.Container) any {
+			bus := &events.RedisEventBus{}
+			if err := container.Inject(bus); err != nil {
+				panic("Failed to inject EventBus dependencies: " + err.Error())
+			}
+			return bus
+		}).
+		AddFactory("EventBus", func(container *xcomp.Container) any {
+			return xcomp.NewInProcessEventBus()
+		}).
+		// Eager: an unreachable database fails registration instead of the first request
+		AddEagerFactory("DatabaseConnection", func(container *xcomp.Container) any {
+			// Inject connects through DatabaseConnection.Initialize
 			dbConn := &database.DatabaseConnection{}
 			if err := container.Inject(dbConn); err != nil {
-				if logger, ok := container.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject DatabaseConnection dependencies",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject DatabaseConnection dependencies: " + err.Error())
-			}
-			if err := dbConn.Initialize(); err != nil {
-				if logger, ok := container.Get("Logger").(xcomp.Logger); ok {
-					logger.Fatal("Failed to initialize database connection",
-						xcomp.Field("error", err))
-				} else {
-					log.Fatalf("Failed to initialize database connection: %v", err)
-				}
+				panic(fmt.Errorf("failed to set up database connection: %w", err))
 			}
 			if logger, ok := container.Get("Logger").(xcomp.Logger); ok {
 				logger.Info("Database connection initialized successfully")
 			}
 			return dbConn.GetDB()
 		}).
+		AddFactory("TxManager", func(container *xcomp.Container) any {
+			txManager := &database.TxManager{}
+			if err := container.Inject(txManager); err != nil {
+				panic("Failed to inject TxManager dependencies: " + err.Error())
+			}
+			return txManager
+		}).
 		Build()
 }
 
-func createAppModule(container *xcomp.Container) xcomp.Module {
-	infrastructureModule := createInfrastructureModule(container)
+func createAppModule(configService *xcomp.ConfigService) xcomp.Module {
+	infrastructureModule := createInfrastructureModule(configService)
 	productModule := product.CreateProductModule()
 	orderModule := order.NewOrderModule()
 	customerModule := customer.CreateCustomerModule()
 	transportModule := CreateTransportModule()
 
-	// Register all business modules first - do NOT include AsyncModule here
 	return xcomp.NewModule().
 		Import(infrastructureModule).
+		Import(xcomp.NewMetricsModule()).
+		Import(xcomp.NewSerializerModule()).
+		Import(xcomp.NewValidatorModule()).
 		Import(productModule).
 		Import(orderModule).
 		Import(customerModule).
 		Import(transportModule).
+		Import(async.CreateAsyncModule()).
 		Build()
 }
 
-func setupFiberApp(configService *xcomp.ConfigService) *fiber.App {
+func setupFiberApp(configService *xcomp.ConfigService, serializer xcomp.Serializer, limits serverLimits, errorHandler fiber.ErrorHandler) *fiber.App {
+	xcomp.SetMoneyFormat(xcomp.ParseMoneyFormat(configService.GetString("server.money_format", "number")))
+
 	app := fiber.New(fiber.Config{
-		ReadTimeout:  time.Duration(configService.GetInt("server.read_timeout_seconds", 30)) * time.Second,
-		WriteTimeout: time.Duration(configService.GetInt("server.write_timeout_seconds", 30)) * time.Second,
-		IdleTimeout:  time.Duration(configService.GetInt("server.timeout_seconds", 30)) * time.Second,
+		ReadTimeout:  limits.ReadTimeout,
+		WriteTimeout: limits.WriteTimeout,
+		IdleTimeout:  limits.IdleTimeout,
+		BodyLimit:    limits.BodyLimit,
+		Concurrency:  limits.Concurrency,
 		Prefork:      configService.GetBool("server.prefork", false),
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-
-			return c.Status(code).JSON(fiber.Map{
-				"error":   "Request failed",
-				"message": err.Error(),
-			})
-		},
+		ErrorHandler: errorHandler,
+		JSONEncoder:  serializer.Marshal,
+		JSONDecoder:  serializer.Unmarshal,
 	})
 
-	app.Use(recover.New())
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: configService.IsDevelopment(),
+	}))
+	if configService.GetBool("server.compression.enabled", true) {
+		app.Use(xcomp.NewCompressionMiddleware(xcomp.CompressionConfig{
+			Level:   xcomp.CompressionLevel(configService.GetInt("server.compression.level", 0)),
+			MinSize: configService.GetInt("server.compression.min_size_bytes", 1024),
+		}))
+	}
+	if configService.GetBool("server.request_id.enabled", true) {
+		app.Use(xcomp.NewRequestIDMiddleware())
+	}
+	if configService.GetBool("server.tenancy.enabled", false) {
+		app.Use(xcomp.NewTenantMiddleware(configService.GetString("server.tenancy.header", xcomp.TenantHeader)))
+	}
+	app.Use(xcomp.NewLocaleMiddleware(configService.GetStringSlice("server.locales", []string{"en"})...))
 	app.Use(logger.New(logger.Config{
-		Format: "${time} ${method} ${path} - ${status} - ${latency}\n",
+		Format: "${time} ${locals:request_id} ${method} ${path} - ${status} - ${latency}\n",
 	}))
 
-	if configService.GetBool("server.cors.enabled", true) {
-		allowedOrigins := configService.GetString("server.cors.allowed_origins", "*")
-		allowedMethods := configService.GetString("server.cors.allowed_methods", "GET,POST,PUT,DELETE,OPTIONS,PATCH")
-		allowedHeaders := configService.GetString("server.cors.allowed_headers", "Content-Type,Authorization")
+	// Wildcard origins are a development convenience only; production must list them explicitly
+	corsEnabled := configService.GetBool("server.cors.enabled", true)
+	if corsEnabled && configService.IsProduction() && configService.Get("server.cors.allowed_origins") == nil {
+		corsEnabled = false
+	}
+
+	if corsEnabled {
+		allowedOrigins := configService.GetStringSlice("server.cors.allowed_origins", []string{"*"})
+		allowedMethods := configService.GetStringSlice("server.cors.allowed_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"})
+		allowedHeaders := configService.GetStringSlice("server.cors.allowed_headers", []string{"Content-Type", "Authorization"})
 
 		app.Use(cors.New(cors.Config{
-			AllowOrigins: allowedOrigins,
-			AllowMethods: allowedMethods,
-			AllowHeaders: allowedHeaders,
+			AllowOrigins: strings.Join(allowedOrigins, ","),
+			AllowMethods: strings.Join(allowedMethods, ","),
+			AllowHeaders: strings.Join(allowedHeaders, ","),
 		}))
 	}
 
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":    "healthy",
-			"timestamp": time.Now().Unix(),
-			"service":   configService.GetString("app.name", "API Server"),
-			"version":   Version,
-		})
-	})
-
 	return app
 }
 
 func serveCommand(c *cli.Context) error {
 	container := xcomp.NewContainer()
+	container.SetStrictInject(true)
+
+	// One ConfigService serves the module filter, the container and the checks below
+	configService := xcomp.NewConfigService(configFilePath())
 
-	appModule := createAppModule(container)
+	// Module switches are read before registration so disabled modules never reach the container
+	container.SetModuleFilter(xcomp.ModuleEnabledFromConfig(configService))
+
+	appModule := createAppModule(configService)
 	if err := container.RegisterModule(appModule); err != nil {
 		return fmt.Errorf("failed to register app module: %w", err)
 	}
 
-	configService, ok := container.Get("ConfigService").(*xcomp.ConfigService)
-	if !ok {
-		return fmt.Errorf("failed to get ConfigService from container")
-	}
-
 	logger, ok := container.Get("Logger").(xcomp.Logger)
 	if !ok {
 		return fmt.Errorf("failed to get Logger from container")
 	}
+	// Flush buffered entries, e.g. to logging.output_paths files, on every exit path
+	defer logger.Sync()
+
+	// A provider name registered twice resolves to whichever module came first
+	for _, warning := range container.RegistrationWarnings() {
+		logger.Warn("Duplicate provider registration", xcomp.String("detail", warning))
+	}
+
+	// Cold-start cost of each lazy singleton, including the eager ones built above
+	metrics, _ := container.Get("Metrics").(*xcomp.Metrics)
+	container.ObserveConstructions(xcomp.LogConstructions(logger, metrics))
+
+	// Fail fast on settings that have no sensible default
+	if err := configService.RequireKeys([]string{"app.name", "database.url"}); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 
 	logger.Info("Starting API Server",
 		xcomp.Field("version", Version),
 		xcomp.Field("build_time", BuildTime),
 		xcomp.Field("git_commit", GitCommit),
-		xcomp.Field("environment", configService.GetString("app.environment", "development")),
+		xcomp.Field("environment", configService.Environment().String()),
 		xcomp.Field("name", configService.GetString("app.name", "API Server")))
 
 	services := container.ListServices()
@@ -177,68 +256,62 @@ func serveCommand(c *cli.Context) error {
 		xcomp.Field("registered_services_count", len(services)),
 		xcomp.Field("services", services))
 
-	app := setupFiberApp(configService)
-
-	// Setup centralized routes
-	setupRoutes(app, container)
-	logger.Debug("All routes registered")
-
-	// Create AsyncService AFTER all modules are registered and dependencies are available
-	redisClient, ok := container.Get("RedisClient").(*redis.Client)
-	if !ok || redisClient == nil {
-		return fmt.Errorf("failed to get RedisClient from container")
-	}
+	reloadCtx, reloadCancel := context.WithCancel(context.Background())
+	defer reloadCancel()
 
-	orderService, ok := container.Get("OrderService").(orderInterfaces.OrderService)
-	if !ok || orderService == nil {
-		return fmt.Errorf("failed to get OrderService from container")
+	// SIGHUP re-reads the config files; subscribers learn what changed through the EventBus
+	if eventBus, ok := container.Get("EventBus").(xcomp.EventBus); ok {
+		xcomp.PublishConfigChanges(configService, eventBus, logger)
 	}
-
-	customerService, ok := container.Get("CustomerService").(customerInterfaces.CustomerService)
-	if !ok || customerService == nil {
-		return fmt.Errorf("failed to get CustomerService from container")
+	configService.ReloadOnSignal(reloadCtx, logger)
+
+	// The HTTP API can be switched off to run a worker-only process
+	var app *fiber.App
+	if container.ModuleEnabled("http") {
+		var err error
+		app, err = setupHTTPServer(container, configService, logger)
+		if err != nil {
+			return err
+		}
+	} else {
+		logger.Info("HTTP module disabled, not serving the API")
 	}
 
-	logger.Info("Creating AsyncService manually after all dependencies are available")
-	asyncService := async.NewAsyncService(redisClient, orderService, customerService, logger)
-
 	asyncCtx, asyncCancel := context.WithCancel(context.Background())
 	defer asyncCancel()
 
-	if err := asyncService.Start(asyncCtx); err != nil {
-		return fmt.Errorf("failed to start async service: %w", err)
+	if !container.ModuleEnabled("async") {
+		logger.Info("Async module disabled, background jobs are not running")
 	}
 
-	// Setup asynq monitoring endpoint
-	monitorHandler := asyncService.GetMonitorHandler()
-	go func() {
-		monitorPort := configService.GetInt("async.monitor.port", 8080)
-		logger.Info("Asynq monitor starting",
-			xcomp.Field("port", monitorPort),
-			xcomp.Field("path", "/monitoring"))
-
-		if err := http.ListenAndServe(fmt.Sprintf(":%d", monitorPort), monitorHandler); err != nil {
-			logger.Error("Asynq monitor failed to start",
-				xcomp.Field("port", monitorPort),
-				xcomp.Field("error", err))
-		}
-	}()
+	// Runs the module OnStart hooks, e.g. the async module's job server and scheduler
+	if err := container.StartModules(asyncCtx); err != nil {
+		return fmt.Errorf("failed to start modules: %w", err)
+	}
 
-	port := c.Int("port")
-	if port == 0 {
-		port = configService.GetInt("app.port", 3000)
+	if redisEventsEnabled(container) {
+		if eventBus, ok := container.Get("EventBus").(*events.RedisEventBus); ok {
+			go eventBus.Start(asyncCtx)
+		}
 	}
 
-	go func() {
-		logger.Info("HTTP server starting",
-			xcomp.Field("port", port),
-			xcomp.Field("address", fmt.Sprintf(":%d", port)))
-		if err := app.Listen(fmt.Sprintf(":%d", port)); err != nil {
-			logger.Error("Server failed to start",
-				xcomp.Field("port", port),
-				xcomp.Field("error", err))
+	if app != nil {
+		port := c.Int("port")
+		if port == 0 {
+			port = configService.GetInt("app.port", 3000)
 		}
-	}()
+
+		go func() {
+			logger.Info("HTTP server starting",
+				xcomp.Field("port", port),
+				xcomp.Field("address", fmt.Sprintf(":%d", port)))
+			if err := app.Listen(fmt.Sprintf(":%d", port)); err != nil {
+				logger.Error("Server failed to start",
+					xcomp.Field("port", port),
+					xcomp.Err(err))
+			}
+		}()
+	}
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -246,17 +319,147 @@ func serveCommand(c *cli.Context) error {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	shutdownStart := time.Now()
+
+	// Steps run last-added first: modules, then HTTP, then the connections
+	shutdown := xcomp.NewShutdownManager(logger, 10*time.Second)
+	// Closes the database pool, the Redis client and any other service the container built
+	shutdown.AddContainer(container)
+	if app != nil {
+		shutdown.AddWithTimeout("http", 30*time.Second, app.ShutdownWithContext)
+	}
+	// Stop the modules first so no job runs against closed connections
+	shutdown.AddWithTimeout("modules", 30*time.Second, func(ctx context.Context) error {
+		asyncCancel()
+		return container.StopModules(ctx)
+	})
 
-	// Cancel async context first
-	asyncCancel()
-
-	// Shutdown Fiber server
-	if err := app.ShutdownWithTimeout(30 * time.Second); err != nil {
-		logger.Error("Server forced to shutdown", xcomp.Field("error", err))
+	if err := shutdown.Shutdown(context.Background()); err != nil {
+		logger.Error("Server forced to shutdown",
+			xcomp.Err(err),
+			xcomp.Field("total_duration", time.Since(shutdownStart).String()))
 		return err
 	}
 
-	logger.Info("Server exited successfully")
+	logger.Info("Server exited successfully",
+		xcomp.Field("total_duration", time.Since(shutdownStart).String()))
+	return nil
+}
+
+// setupHTTPServer builds the Fiber app with middleware and the routes of every enabled module
+func setupHTTPServer(container *xcomp.Container, configService *xcomp.ConfigService, logger xcomp.Logger) (*fiber.App, error) {
+	serializer, ok := container.Get("Serializer").(xcomp.Serializer)
+	if !ok {
+		serializer = xcomp.JSONSerializer{}
+	}
+	app := setupFiberApp(configService, serializer, loadServerLimits(configService, logger), xcomp.FiberErrorHandler(logger))
+	// Handlers log through xcomp.LoggerFromContext(c.UserContext()) to get request and trace IDs
+	app.Use(xcomp.NewLoggerMiddleware(logger))
+
+	// Readiness: checks every built service that can report its health, e.g. the database pool
+	app.Get("/health", xcomp.NewHealthHandler(container, 5*time.Second))
+
+	if metrics, ok := container.Get("Metrics").(*xcomp.Metrics); ok {
+		app.Get("/metrics", adaptor.HTTPHandler(metrics))
+	}
+
+	setupAdminRoutes(app, container, configService, logger)
+
+	if configService.GetBool("server.idempotency.enabled", true) {
+		store, ok := container.Get("IdempotencyStore").(xcomp.IdempotencyStore)
+		if !ok {
+			return nil, fmt.Errorf("failed to get IdempotencyStore from container")
+		}
+
+		app.Use(xcomp.NewIdempotencyMiddleware(xcomp.IdempotencyConfig{
+			Store:   store,
+			Methods: strings.Split(configService.GetString("server.idempotency.methods", "POST,PATCH"), ","),
+			TTL:     configService.GetDuration("server.idempotency.ttl_seconds", 24*time.Hour),
+			LockTTL: configService.GetDuration("server.idempotency.lock_ttl_seconds", 30*time.Second),
+			Logger:  logger,
+		}))
+	}
+
+	// Each module declares its routes with AddRoutes
+	if configService.GetBool("server.route_reload", configService.IsDevelopment()) {
+		// Routes live in a rebuildable sub-app, rebuilt from the shared config once
+		// its SIGHUP reload has swapped the new values in
+		router, err := xcomp.NewReloadableRouter(func() (*fiber.App, error) {
+			routes := fiber.New(fiber.Config{ErrorHandler: xcomp.FiberErrorHandler(logger)})
+			if err := setupRoutes(routes, container, configService.GetString("server.api_prefix", "/api/v1")); err != nil {
+				return nil, err
+			}
+			return routes, nil
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build routes: %w", err)
+		}
+
+		app.Use(router.Handler())
+		configService.OnReload(func(changedKeys []string) {
+			if err := router.Reload(); err != nil {
+				logger.Error("Route reload failed, keeping previous routes", xcomp.Err(err))
+				return
+			}
+			logger.Info("Routes reloaded", xcomp.Int("changed_keys", len(changedKeys)))
+		})
+		logger.Info("Route reload enabled, send SIGHUP to rebuild routes")
+	} else {
+		if err := setupRoutes(app, container, configService.GetString("server.api_prefix", "/api/v1")); err != nil {
+			return nil, fmt.Errorf("failed to mount routes: %w", err)
+		}
+	}
+	logger.Debug("All routes registered")
+
+	return app, nil
+}
+
+// healthCommand checks the dependencies the server needs and exits non-zero if any is down,
+// so it can serve as a readiness probe and Docker HEALTHCHECK
+func healthCommand(c *cli.Context) error {
+	container := xcomp.NewContainer()
+	configService := xcomp.NewConfigService(configFilePath())
+	container.Register("ConfigService", configService)
+	container.Register("Logger", xcomp.NewLogger(configService))
+
+	checker := xcomp.NewHealthChecker()
+	checker.Register("database", func(ctx context.Context) error {
+		dbConn := &database.DatabaseConnection{NoRetry: true}
+		if err := container.Inject(dbConn); err != nil {
+			return err
+		}
+		defer dbConn.Close()
+		return dbConn.HealthCheck(ctx)
+	})
+	if configService.GetBool("redis.enabled", true) {
+		checker.Register("redis", func(ctx context.Context) error {
+			redisService := &database.RedisService{NoRetry: true}
+			if err := container.Inject(redisService); err != nil {
+				return err
+			}
+			defer redisService.Close()
+			return redisService.GetClient().Ping(ctx).Err()
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Duration("timeout"))
+	defer cancel()
+
+	report := checker.Check(ctx)
+	for _, result := range report.Checks {
+		if result.Status == xcomp.HealthStatusUp {
+			fmt.Printf("✅ %s (%s)\n", result.Name, result.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Printf("❌ %s: %s\n", result.Name, result.Error)
+		}
+	}
+
+	if !report.Healthy() {
+		return cli.Exit("Application is unhealthy", 1)
+	}
+
+	fmt.Println("✅ Application is healthy")
+	fmt.Printf("Version: %s\n", Version)
 	return nil
 }
 
@@ -306,12 +509,25 @@ func main() {
 			},
 			{
 				Name:  "health",
-				Usage: "Check application health",
+				Usage: "Check that the database and Redis are reachable",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Configuration file path",
+						EnvVars: []string{"CONFIG_FILE"},
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "Maximum time to wait for all checks",
+						Value: 3 * time.Second,
+					},
+				},
 				Action: func(c *cli.Context) error {
-					fmt.Println("✅ Application is healthy")
-					fmt.Printf("Version: %s\n", Version)
-					fmt.Printf("Build Time: %s\n", BuildTime)
-					return nil
+					if configFile := c.String("config"); configFile != "" {
+						os.Setenv("CONFIG_FILE", configFile)
+					}
+					return healthCommand(c)
 				},
 			},
 		},
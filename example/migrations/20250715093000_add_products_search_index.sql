@@ -0,0 +1,11 @@
+-- +goose Up
+-- Full-text search over name, category and description, weighted in that order.
+-- SearchProducts repeats this exact expression so the planner can use the index.
+CREATE INDEX idx_products_search ON products USING GIN ((
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('english', coalesce(category, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_search;
@@ -3,6 +3,9 @@ package dto
 import (
 	"time"
 
+	"example/modules/customer/domain/entities"
+	"xcomp"
+
 	"github.com/google/uuid"
 )
 
@@ -16,6 +19,18 @@ type UpdateCustomerRequest struct {
 	Email    string `json:"email" validate:"required,email,max=255"`
 }
 
+func (r *CreateCustomerRequest) ToEntity() *entities.Customer {
+	return &entities.Customer{
+		Username: r.Username,
+		Email:    r.Email,
+	}
+}
+
+func (r *UpdateCustomerRequest) ApplyTo(customer *entities.Customer) {
+	customer.Username = r.Username
+	customer.Email = r.Email
+}
+
 type CustomerResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
@@ -24,13 +39,7 @@ type CustomerResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-type CustomerListResponse struct {
-	Customers  []*CustomerResponse `json:"customers"`
-	TotalCount int64               `json:"total_count"`
-	Page       int32               `json:"page"`
-	PageSize   int32               `json:"page_size"`
-	TotalPages int32               `json:"total_pages"`
-}
+type CustomerListResponse = xcomp.Page[*CustomerResponse]
 
 type CustomerSearchRequest struct {
 	Query    string `json:"query" validate:"required,min=1"`
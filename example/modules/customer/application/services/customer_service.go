@@ -2,65 +2,83 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"example/modules/customer/application/dto"
 	"example/modules/customer/domain/entities"
 	"example/modules/customer/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 )
 
 type CustomerService struct {
-	customerRepository      interfaces.CustomerRepository      // lowercase - manual injection
-	customerCacheRepository interfaces.CustomerCacheRepository // lowercase - manual injection
+	customerRepository      interfaces.CustomerRepository      // lowercase - setter injection
+	customerCacheRepository interfaces.CustomerCacheRepository // lowercase - setter injection
+	orderChecker            interfaces.CustomerOrderChecker    // optional - nil when the order module is disabled
+
+	SearchMinQueryLength int `config:"search.min_query_length"`
+	SearchMaxQueryLength int `config:"search.max_query_length"`
 }
 
 func NewCustomerService() *CustomerService {
-	return &CustomerService{}
+	return &CustomerService{
+		SearchMinQueryLength: 2,
+		SearchMaxQueryLength: 100,
+	}
 }
 
-// Method injection for lowercase fields
-func (cs *CustomerService) SetDependencies(
-	customerRepository interfaces.CustomerRepository,
-	customerCacheRepository interfaces.CustomerCacheRepository,
-) {
+// InjectSetters lets the container set the lowercase fields through their setters
+func (cs *CustomerService) InjectSetters() map[string]string {
+	return map[string]string{
+		"SetCustomerRepository":      "CustomerRepository",
+		"SetCustomerCacheRepository": "CustomerCacheRepository",
+		// Provided by the order module when it is enabled
+		"SetOrderChecker": "OrderService,optional",
+	}
+}
+
+func (cs *CustomerService) SetCustomerRepository(customerRepository interfaces.CustomerRepository) {
 	cs.customerRepository = customerRepository
+}
+
+func (cs *CustomerService) SetCustomerCacheRepository(customerCacheRepository interfaces.CustomerCacheRepository) {
 	cs.customerCacheRepository = customerCacheRepository
 }
 
+func (cs *CustomerService) SetOrderChecker(orderChecker interfaces.CustomerOrderChecker) {
+	cs.orderChecker = orderChecker
+}
+
 func (cs *CustomerService) GetServiceName() string {
 	return "CustomerService"
 }
 
 func (cs *CustomerService) CreateCustomer(ctx context.Context, req *dto.CreateCustomerRequest) (*dto.CustomerResponse, error) {
-	customer := &entities.Customer{
-		Username: req.Username,
-		Email:    req.Email,
-	}
-
-	if err := customer.Validate(); err != nil {
+	customer, err := xcomp.ToEntity(req)
+	if err != nil {
 		return nil, err
 	}
 
-	existingCustomer, _ := cs.customerRepository.GetByUsername(ctx, req.Username)
-	if existingCustomer != nil {
-		return nil, entities.ErrCustomerUsernameExists
-	}
-
-	existingCustomer, _ = cs.customerRepository.GetByEmail(ctx, req.Email)
-	if existingCustomer != nil {
-		return nil, entities.ErrCustomerEmailExists
-	}
-
-	createdCustomer, err := cs.customerRepository.Create(ctx, customer)
+	// The unique constraints decide, so two concurrent requests can't both create the customer
+	createdCustomer, created, err := cs.customerRepository.FindOrCreate(ctx, customer)
 	if err != nil {
 		return nil, err
 	}
+	if !created {
+		if createdCustomer.Username == req.Username {
+			return nil, entities.ErrCustomerUsernameExists
+		}
+		return nil, entities.ErrCustomerEmailExists
+	}
 
-	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerCacheKey(createdCustomer.ID), createdCustomer, 30*time.Minute)
-	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerUsernameCacheKey(createdCustomer.Username), createdCustomer, 30*time.Minute)
-	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerEmailCacheKey(createdCustomer.Email), createdCustomer, 30*time.Minute)
+	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerCacheKey(ctx, createdCustomer.ID), createdCustomer, 30*time.Minute)
+	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerUsernameCacheKey(ctx, createdCustomer.Username), createdCustomer, 30*time.Minute)
+	cs.customerCacheRepository.Set(ctx, cs.customerCacheRepository.GetCustomerEmailCacheKey(ctx, createdCustomer.Email), createdCustomer, 30*time.Minute)
 
 	return cs.mapToCustomerResponse(createdCustomer), nil
 }
@@ -88,10 +106,10 @@ func (cs *CustomerService) UpdateCustomer(ctx context.Context, id uuid.UUID, req
 		}
 	}
 
-	existingCustomer.Username = req.Username
-	existingCustomer.Email = req.Email
+	// ApplyTo overwrites these, and the cache entries under the old values must go too
+	previousUsername, previousEmail := existingCustomer.Username, existingCustomer.Email
 
-	if err := existingCustomer.Validate(); err != nil {
+	if err := xcomp.ApplyTo(req, existingCustomer); err != nil {
 		return nil, err
 	}
 
@@ -100,14 +118,14 @@ func (cs *CustomerService) UpdateCustomer(ctx context.Context, id uuid.UUID, req
 		return nil, err
 	}
 
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerCacheKey(updatedCustomer.ID))
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerUsernameCacheKey(updatedCustomer.Username))
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerEmailCacheKey(updatedCustomer.Email))
+	cs.invalidateCustomerCache(ctx, updatedCustomer.ID,
+		[]string{previousUsername, updatedCustomer.Username},
+		[]string{previousEmail, updatedCustomer.Email})
 
 	return cs.mapToCustomerResponse(updatedCustomer), nil
 }
 
-func (cs *CustomerService) DeleteCustomer(ctx context.Context, id uuid.UUID) error {
+func (cs *CustomerService) DeleteCustomer(ctx context.Context, id uuid.UUID, force bool) error {
 	existingCustomer, err := cs.customerRepository.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -116,19 +134,50 @@ func (cs *CustomerService) DeleteCustomer(ctx context.Context, id uuid.UUID) err
 		return entities.ErrCustomerNotFound
 	}
 
+	if !force && cs.orderChecker != nil {
+		hasOrders, err := cs.orderChecker.HasOrders(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check customer orders: %w", err)
+		}
+		if hasOrders {
+			return entities.ErrCustomerHasOrders.Withf("customer %s is referenced by existing orders, remove them first or delete with force", id)
+		}
+	}
+
 	if err := cs.customerRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerCacheKey(id))
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerUsernameCacheKey(existingCustomer.Username))
-	cs.customerCacheRepository.Delete(ctx, cs.customerCacheRepository.GetCustomerEmailCacheKey(existingCustomer.Email))
+	cs.invalidateCustomerCache(ctx, id,
+		[]string{existingCustomer.Username},
+		[]string{existingCustomer.Email})
 
 	return nil
 }
 
+// invalidateCustomerCache drops the id entry and the username and email lookups in
+// one call, so no reader sees some keys gone and others stale
+func (cs *CustomerService) invalidateCustomerCache(ctx context.Context, id uuid.UUID, usernames, emails []string) {
+	keys := []string{cs.customerCacheRepository.GetCustomerCacheKey(ctx, id)}
+	seen := map[string]bool{keys[0]: true}
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, username := range usernames {
+		add(cs.customerCacheRepository.GetCustomerUsernameCacheKey(ctx, username))
+	}
+	for _, email := range emails {
+		add(cs.customerCacheRepository.GetCustomerEmailCacheKey(ctx, email))
+	}
+
+	cs.customerCacheRepository.Delete(ctx, keys...)
+}
+
 func (cs *CustomerService) GetCustomer(ctx context.Context, id uuid.UUID) (*dto.CustomerResponse, error) {
-	cacheKey := cs.customerCacheRepository.GetCustomerCacheKey(id)
+	cacheKey := cs.customerCacheRepository.GetCustomerCacheKey(ctx, id)
 	if cachedCustomer, _ := cs.customerCacheRepository.Get(ctx, cacheKey); cachedCustomer != nil {
 		return cs.mapToCustomerResponse(cachedCustomer), nil
 	}
@@ -147,7 +196,7 @@ func (cs *CustomerService) GetCustomer(ctx context.Context, id uuid.UUID) (*dto.
 }
 
 func (cs *CustomerService) GetCustomerByUsername(ctx context.Context, username string) (*dto.CustomerResponse, error) {
-	cacheKey := cs.customerCacheRepository.GetCustomerUsernameCacheKey(username)
+	cacheKey := cs.customerCacheRepository.GetCustomerUsernameCacheKey(ctx, username)
 	if cachedCustomer, _ := cs.customerCacheRepository.Get(ctx, cacheKey); cachedCustomer != nil {
 		return cs.mapToCustomerResponse(cachedCustomer), nil
 	}
@@ -166,7 +215,7 @@ func (cs *CustomerService) GetCustomerByUsername(ctx context.Context, username s
 }
 
 func (cs *CustomerService) GetCustomerByEmail(ctx context.Context, email string) (*dto.CustomerResponse, error) {
-	cacheKey := cs.customerCacheRepository.GetCustomerEmailCacheKey(email)
+	cacheKey := cs.customerCacheRepository.GetCustomerEmailCacheKey(ctx, email)
 	if cachedCustomer, _ := cs.customerCacheRepository.Get(ctx, cacheKey); cachedCustomer != nil {
 		return cs.mapToCustomerResponse(cachedCustomer), nil
 	}
@@ -185,14 +234,7 @@ func (cs *CustomerService) GetCustomerByEmail(ctx context.Context, email string)
 }
 
 func (cs *CustomerService) ListCustomers(ctx context.Context, page, pageSize int32) (*dto.CustomerListResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 	customers, err := cs.customerRepository.List(ctx, pageSize, offset)
 	if err != nil {
 		return nil, err
@@ -203,52 +245,27 @@ func (cs *CustomerService) ListCustomers(ctx context.Context, page, pageSize int
 		return nil, err
 	}
 
-	customerResponses := make([]*dto.CustomerResponse, len(customers))
-	for i, customer := range customers {
-		customerResponses[i] = cs.mapToCustomerResponse(customer)
-	}
-
-	totalPages := int32((totalCount + int64(pageSize) - 1) / int64(pageSize))
-
-	return &dto.CustomerListResponse{
-		Customers:  customerResponses,
-		TotalCount: totalCount,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
+	response := xcomp.MapPage(customers, totalCount, page, pageSize, cs.mapToCustomerResponse)
+	return &response, nil
 }
 
 func (cs *CustomerService) SearchCustomers(ctx context.Context, req *dto.CustomerSearchRequest) (*dto.CustomerListResponse, error) {
-	if req.Page < 1 {
-		req.Page = 1
-	}
-	if req.PageSize < 1 || req.PageSize > 100 {
-		req.PageSize = 10
-	}
+	page, pageSize, offset := xcomp.PageOffset(req.Page, req.PageSize)
 
-	offset := (req.Page - 1) * req.PageSize
-	customers, err := cs.customerRepository.Search(ctx, req.Query, req.PageSize, offset)
+	query, err := normalizeSearchQuery(req.Query, cs.SearchMinQueryLength, cs.SearchMaxQueryLength)
 	if err != nil {
 		return nil, err
 	}
 
-	totalCount := int64(len(customers))
-
-	customerResponses := make([]*dto.CustomerResponse, len(customers))
-	for i, customer := range customers {
-		customerResponses[i] = cs.mapToCustomerResponse(customer)
+	customers, err := cs.customerRepository.Search(ctx, query, pageSize, offset)
+	if err != nil {
+		return nil, err
 	}
 
-	totalPages := int32((totalCount + int64(req.PageSize) - 1) / int64(req.PageSize))
+	totalCount := int64(len(customers))
 
-	return &dto.CustomerListResponse{
-		Customers:  customerResponses,
-		TotalCount: totalCount,
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		TotalPages: totalPages,
-	}, nil
+	response := xcomp.MapPage(customers, totalCount, page, pageSize, cs.mapToCustomerResponse)
+	return &response, nil
 }
 
 func (cs *CustomerService) mapToCustomerResponse(customer *entities.Customer) *dto.CustomerResponse {
@@ -260,3 +277,17 @@ func (cs *CustomerService) mapToCustomerResponse(customer *entities.Customer) *d
 		UpdatedAt: customer.UpdatedAt,
 	}
 }
+
+// normalizeSearchQuery trims and collapses whitespace, then enforces the length
+// bounds; one-character queries match nearly every row and scan the whole table
+func normalizeSearchQuery(query string, minLength, maxLength int) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	length := utf8.RuneCountInString(query)
+	if length < minLength {
+		return "", entities.ErrSearchQueryTooShort.Withf("use at least %d characters", minLength)
+	}
+	if maxLength > 0 && length > maxLength {
+		return "", entities.ErrSearchQueryTooLong.Withf("use at most %d characters", maxLength)
+	}
+	return query, nil
+}
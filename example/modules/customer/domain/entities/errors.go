@@ -1,13 +1,15 @@
 package entities
 
-import (
-	"errors"
-)
+import "xcomp"
 
+// Each error carries the HTTP status it is reported with, see xcomp.AppError
 var (
-	ErrCustomerNotFound         = errors.New("customer not found")
-	ErrCustomerUsernameRequired = errors.New("customer username is required")
-	ErrCustomerEmailRequired    = errors.New("customer email is required")
-	ErrCustomerUsernameExists   = errors.New("customer username already exists")
-	ErrCustomerEmailExists      = errors.New("customer email already exists")
+	ErrCustomerNotFound         = xcomp.NewNotFound("customer not found")
+	ErrCustomerUsernameRequired = xcomp.NewValidation("customer username is required")
+	ErrCustomerEmailRequired    = xcomp.NewValidation("customer email is required")
+	ErrCustomerUsernameExists   = xcomp.NewConflict("customer username already exists")
+	ErrCustomerEmailExists      = xcomp.NewConflict("customer email already exists")
+	ErrCustomerHasOrders        = xcomp.NewConflict("customer has orders")
+	ErrSearchQueryTooShort      = xcomp.NewBadRequest("search query is too short")
+	ErrSearchQueryTooLong       = xcomp.NewBadRequest("search query is too long")
 )
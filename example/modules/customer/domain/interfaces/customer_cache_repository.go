@@ -12,8 +12,10 @@ import (
 type CustomerCacheRepository interface {
 	Set(ctx context.Context, key string, customer *entities.Customer, ttl time.Duration) error
 	Get(ctx context.Context, key string) (*entities.Customer, error)
-	Delete(ctx context.Context, key string) error
-	GetCustomerCacheKey(id uuid.UUID) string
-	GetCustomerUsernameCacheKey(username string) string
-	GetCustomerEmailCacheKey(email string) string
+	// Delete removes every key in one round trip
+	Delete(ctx context.Context, keys ...string) error
+	// Key builders scope keys to the request's tenant, see xcomp.TenantCacheKey
+	GetCustomerCacheKey(ctx context.Context, id uuid.UUID) string
+	GetCustomerUsernameCacheKey(ctx context.Context, username string) string
+	GetCustomerEmailCacheKey(ctx context.Context, email string) string
 }
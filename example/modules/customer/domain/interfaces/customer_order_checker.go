@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CustomerOrderChecker is the port the order module fulfils so customers with orders
+// aren't deleted out from under them
+type CustomerOrderChecker interface {
+	HasOrders(ctx context.Context, customerID uuid.UUID) (bool, error)
+}
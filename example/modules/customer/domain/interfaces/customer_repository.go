@@ -10,6 +10,9 @@ import (
 
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error)
+	// FindOrCreate atomically inserts customer or returns the existing customer with its
+	// username or email; created is false in the latter case
+	FindOrCreate(ctx context.Context, customer *entities.Customer) (result *entities.Customer, created bool, err error)
 	Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Customer, error)
@@ -11,7 +11,8 @@ import (
 type CustomerService interface {
 	CreateCustomer(ctx context.Context, req *dto.CreateCustomerRequest) (*dto.CustomerResponse, error)
 	UpdateCustomer(ctx context.Context, id uuid.UUID, req *dto.UpdateCustomerRequest) (*dto.CustomerResponse, error)
-	DeleteCustomer(ctx context.Context, id uuid.UUID) error
+	// DeleteCustomer refuses to delete a customer with orders unless force is set
+	DeleteCustomer(ctx context.Context, id uuid.UUID, force bool) error
 	GetCustomer(ctx context.Context, id uuid.UUID) (*dto.CustomerResponse, error)
 	GetCustomerByUsername(ctx context.Context, username string) (*dto.CustomerResponse, error)
 	GetCustomerByEmail(ctx context.Context, email string) (*dto.CustomerResponse, error)
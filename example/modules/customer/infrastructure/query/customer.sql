@@ -31,6 +31,12 @@ INSERT INTO customers (username, email)
 VALUES ($1, $2)
 RETURNING id, username, email, created_at, updated_at;
 
+-- name: CreateCustomerIfAbsent :one
+INSERT INTO customers (username, email)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+RETURNING id, username, email, created_at, updated_at;
+
 -- name: UpdateCustomer :one
 UPDATE customers
 SET username = $2, email = $3, updated_at = CURRENT_TIMESTAMP
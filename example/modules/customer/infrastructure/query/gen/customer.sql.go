@@ -46,6 +46,31 @@ func (q *Queries) CreateCustomer(ctx context.Context, arg CreateCustomerParams)
 	return &i, err
 }
 
+const createCustomerIfAbsent = `-- name: CreateCustomerIfAbsent :one
+INSERT INTO customers (username, email)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+RETURNING id, username, email, created_at, updated_at
+`
+
+type CreateCustomerIfAbsentParams struct {
+	Username string `db:"username"`
+	Email    string `db:"email"`
+}
+
+func (q *Queries) CreateCustomerIfAbsent(ctx context.Context, arg CreateCustomerIfAbsentParams) (*Customer, error) {
+	row := q.db.QueryRow(ctx, createCustomerIfAbsent, arg.Username, arg.Email)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
 const deleteCustomer = `-- name: DeleteCustomer :exec
 DELETE FROM customers
 WHERE id = $1
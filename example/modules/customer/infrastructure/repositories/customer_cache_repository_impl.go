@@ -2,18 +2,21 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"example/modules/customer/domain/entities"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type CustomerCacheRepositoryImpl struct {
-	RedisClient *redis.Client `inject:"RedisClient"`
+	RedisClient  *redis.Client       `inject:"RedisClient"`
+	CacheMetrics *xcomp.CacheMetrics `inject:"CacheMetrics"`
+	Serializer   xcomp.Serializer    `inject:"Serializer"`
 }
 
 func (r *CustomerCacheRepositoryImpl) GetServiceName() string {
@@ -21,7 +24,11 @@ func (r *CustomerCacheRepositoryImpl) GetServiceName() string {
 }
 
 func (r *CustomerCacheRepositoryImpl) Set(ctx context.Context, key string, customer *entities.Customer, ttl time.Duration) error {
-	data, err := json.Marshal(customer)
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	data, err := r.Serializer.Marshal(customer)
 	if err != nil {
 		return err
 	}
@@ -30,34 +37,46 @@ func (r *CustomerCacheRepositoryImpl) Set(ctx context.Context, key string, custo
 }
 
 func (r *CustomerCacheRepositoryImpl) Get(ctx context.Context, key string) (*entities.Customer, error) {
+	if r.RedisClient == nil {
+		r.CacheMetrics.Miss("customer")
+		return nil, nil
+	}
+
 	data, err := r.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
+			r.CacheMetrics.Miss("customer")
 			return nil, nil
 		}
 		return nil, err
 	}
 
 	var customer entities.Customer
-	if err := json.Unmarshal([]byte(data), &customer); err != nil {
+	if err := r.Serializer.Unmarshal([]byte(data), &customer); err != nil {
 		return nil, err
 	}
 
+	r.CacheMetrics.Hit("customer")
 	return &customer, nil
 }
 
-func (r *CustomerCacheRepositoryImpl) Delete(ctx context.Context, key string) error {
-	return r.RedisClient.Del(ctx, key).Err()
+// Delete removes the keys with a single DEL, which Redis applies atomically
+func (r *CustomerCacheRepositoryImpl) Delete(ctx context.Context, keys ...string) error {
+	if r.RedisClient == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return r.RedisClient.Del(ctx, keys...).Err()
 }
 
-func (r *CustomerCacheRepositoryImpl) GetCustomerCacheKey(id uuid.UUID) string {
-	return fmt.Sprintf("customer:id:%s", id.String())
+func (r *CustomerCacheRepositoryImpl) GetCustomerCacheKey(ctx context.Context, id uuid.UUID) string {
+	return xcomp.TenantCacheKey(ctx, fmt.Sprintf("customer:id:%s", id.String()))
 }
 
-func (r *CustomerCacheRepositoryImpl) GetCustomerUsernameCacheKey(username string) string {
-	return fmt.Sprintf("customer:username:%s", username)
+func (r *CustomerCacheRepositoryImpl) GetCustomerUsernameCacheKey(ctx context.Context, username string) string {
+	return xcomp.TenantCacheKey(ctx, fmt.Sprintf("customer:username:%s", username))
 }
 
-func (r *CustomerCacheRepositoryImpl) GetCustomerEmailCacheKey(email string) string {
-	return fmt.Sprintf("customer:email:%s", email)
+func (r *CustomerCacheRepositoryImpl) GetCustomerEmailCacheKey(ctx context.Context, email string) string {
+	return xcomp.TenantCacheKey(ctx, fmt.Sprintf("customer:email:%s", email))
 }
@@ -2,38 +2,39 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"example/infrastructure/database"
 	"example/modules/customer/domain/entities"
 	"example/modules/customer/infrastructure/query/gen"
 
+	"xcomp"
+
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type CustomerRepositoryImpl struct {
 	DB      *pgxpool.Pool `inject:"DatabaseConnection"`
-	Queries *gen.Queries
+	queries xcomp.Lazy[*gen.Queries]
 }
 
 func (r *CustomerRepositoryImpl) GetServiceName() string {
 	return "CustomerRepositoryImpl"
 }
 
-func (r *CustomerRepositoryImpl) Initialize() {
-	if r.DB != nil {
-		r.Queries = gen.New(r.DB)
-	}
+func (r *CustomerRepositoryImpl) q() *gen.Queries {
+	return r.queries.Get(func() *gen.Queries {
+		return gen.New(r.DB)
+	})
 }
 
 func (r *CustomerRepositoryImpl) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	result, err := r.Queries.CreateCustomer(ctx, gen.CreateCustomerParams{
+	result, err := r.q().CreateCustomer(ctx, gen.CreateCustomerParams{
 		Username: customer.Username,
 		Email:    customer.Email,
 	})
@@ -44,17 +45,38 @@ func (r *CustomerRepositoryImpl) Create(ctx context.Context, customer *entities.
 	return r.convertToEntity(result), nil
 }
 
-func (r *CustomerRepositoryImpl) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
+// FindOrCreate inserts customer unless its username or email is taken, in which case
+// it returns the customer holding it
+func (r *CustomerRepositoryImpl) FindOrCreate(ctx context.Context, customer *entities.Customer) (*entities.Customer, bool, error) {
+	result, created, err := database.FindOrCreate(ctx,
+		func(ctx context.Context) (*gen.Customer, error) {
+			return r.q().CreateCustomerIfAbsent(ctx, gen.CreateCustomerIfAbsentParams{
+				Username: customer.Username,
+				Email:    customer.Email,
+			})
+		},
+		func(ctx context.Context) (*gen.Customer, error) {
+			existing, err := r.q().GetCustomerByUsername(ctx, customer.Username)
+			if errors.Is(err, pgx.ErrNoRows) {
+				existing, err = r.q().GetCustomerByEmail(ctx, customer.Email)
+			}
+			return existing, err
+		},
+	)
+	if err != nil {
+		return nil, false, r.convertError("CustomerRepository.FindOrCreate", customer.Username, err)
 	}
 
+	return r.convertToEntity(result), created, nil
+}
+
+func (r *CustomerRepositoryImpl) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(customer.ID.String()); err != nil {
 		return nil, fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	result, err := r.Queries.UpdateCustomer(ctx, gen.UpdateCustomerParams{
+	result, err := r.q().UpdateCustomer(ctx, gen.UpdateCustomerParams{
 		ID:       pgID,
 		Username: customer.Username,
 		Email:    customer.Email,
@@ -67,68 +89,48 @@ func (r *CustomerRepositoryImpl) Update(ctx context.Context, customer *entities.
 }
 
 func (r *CustomerRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(id.String()); err != nil {
 		return fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	return r.Queries.DeleteCustomer(ctx, pgID)
+	return r.q().DeleteCustomer(ctx, pgID)
 }
 
 func (r *CustomerRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(id.String()); err != nil {
 		return nil, fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	result, err := r.Queries.GetCustomer(ctx, pgID)
+	result, err := r.q().GetCustomer(ctx, pgID)
 	if err != nil {
-		return nil, r.convertError(err)
+		return nil, r.convertError("CustomerRepository.GetByID", id, err)
 	}
 
 	return r.convertToEntity(result), nil
 }
 
 func (r *CustomerRepositoryImpl) GetByUsername(ctx context.Context, username string) (*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	result, err := r.Queries.GetCustomerByUsername(ctx, username)
+	result, err := r.q().GetCustomerByUsername(ctx, username)
 	if err != nil {
-		return nil, r.convertError(err)
+		return nil, r.convertError("CustomerRepository.GetByUsername", username, err)
 	}
 
 	return r.convertToEntity(result), nil
 }
 
 func (r *CustomerRepositoryImpl) GetByEmail(ctx context.Context, email string) (*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	result, err := r.Queries.GetCustomerByEmail(ctx, email)
+	result, err := r.q().GetCustomerByEmail(ctx, email)
 	if err != nil {
-		return nil, r.convertError(err)
+		return nil, r.convertError("CustomerRepository.GetByEmail", email, err)
 	}
 
 	return r.convertToEntity(result), nil
 }
 
 func (r *CustomerRepositoryImpl) List(ctx context.Context, limit, offset int32) ([]*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	results, err := r.Queries.ListCustomers(ctx, gen.ListCustomersParams{
+	results, err := r.q().ListCustomers(ctx, gen.ListCustomersParams{
 		Limit:  limit,
 		Offset: offset,
 	})
@@ -145,12 +147,9 @@ func (r *CustomerRepositoryImpl) List(ctx context.Context, limit, offset int32)
 }
 
 func (r *CustomerRepositoryImpl) Search(ctx context.Context, query string, limit, offset int32) ([]*entities.Customer, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	results, err := r.Queries.SearchCustomers(ctx, gen.SearchCustomersParams{
-		Column1: &query,
+	pattern := database.EscapeLike(query)
+	results, err := r.q().SearchCustomers(ctx, gen.SearchCustomersParams{
+		Column1: &pattern,
 		Limit:   limit,
 		Offset:  offset,
 	})
@@ -167,11 +166,7 @@ func (r *CustomerRepositoryImpl) Search(ctx context.Context, query string, limit
 }
 
 func (r *CustomerRepositoryImpl) Count(ctx context.Context) (int64, error) {
-	if r.Queries == nil {
-		r.Initialize()
-	}
-
-	return r.Queries.CountCustomers(ctx)
+	return r.q().CountCustomers(ctx)
 }
 
 func (r *CustomerRepositoryImpl) convertToEntity(sqlcCustomer *gen.Customer) *entities.Customer {
@@ -197,9 +192,9 @@ func (r *CustomerRepositoryImpl) convertToEntity(sqlcCustomer *gen.Customer) *en
 	}
 }
 
-func (r *CustomerRepositoryImpl) convertError(err error) error {
-	if err.Error() == "no rows in result set" {
+func (r *CustomerRepositoryImpl) convertError(op string, id any, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return entities.ErrCustomerNotFound
 	}
-	return fmt.Errorf("database error: %w", err)
+	return xcomp.WrapOp(op, "customer", id, fmt.Errorf("database error: %w", err))
 }
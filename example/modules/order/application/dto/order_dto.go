@@ -1,10 +1,14 @@
 package dto
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"example/modules/order/domain/entities"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 )
 
@@ -17,27 +21,59 @@ type CreateOrderRequest struct {
 }
 
 type CreateOrderItemRequest struct {
-	ProductID   uuid.UUID `json:"product_id" validate:"required"`
-	ProductName string    `json:"product_name" validate:"required"`
-	Quantity    int32     `json:"quantity" validate:"required,min=1"`
-	UnitPrice   float64   `json:"unit_price" validate:"required,min=0.01"`
+	ProductID   uuid.UUID   `json:"product_id" validate:"required"`
+	ProductName string      `json:"product_name" validate:"required"`
+	Quantity    int32       `json:"quantity" validate:"required,min=1"`
+	UnitPrice   xcomp.Money `json:"unit_price" validate:"required,min=0.01"`
 }
 
 type UpdateOrderRequest struct {
 	Status          *entities.OrderStatus `json:"status"`
-	ShippingCost    *float64              `json:"shipping_cost"`
-	TaxAmount       *float64              `json:"tax_amount"`
-	DiscountAmount  *float64              `json:"discount_amount"`
+	ShippingCost    *xcomp.Money          `json:"shipping_cost"`
+	TaxAmount       *xcomp.Money          `json:"tax_amount"`
+	DiscountAmount  *xcomp.Money          `json:"discount_amount"`
 	ShippingAddress *string               `json:"shipping_address"`
 	BillingAddress  *string               `json:"billing_address"`
 	Notes           *string               `json:"notes"`
 }
 
+// Validate rejects the same product appearing on more than one line
+func (r *CreateOrderRequest) Validate() error {
+	seen := make(map[uuid.UUID]bool, len(r.Items))
+	for _, item := range r.Items {
+		if seen[item.ProductID] {
+			return fmt.Errorf("product %s appears more than once, combine the quantities instead", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// Validate covers the rules that depend on more than one field. Whether the discount
+// fits within the order total needs the stored items and is checked by the service.
+func (r *UpdateOrderRequest) Validate() error {
+	for name, amount := range map[string]*xcomp.Money{
+		"shipping_cost":   r.ShippingCost,
+		"tax_amount":      r.TaxAmount,
+		"discount_amount": r.DiscountAmount,
+	} {
+		if amount != nil && *amount < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+
+	if r.Status != nil && *r.Status == entities.OrderStatusShipped &&
+		r.ShippingAddress != nil && strings.TrimSpace(*r.ShippingAddress) == "" {
+		return fmt.Errorf("shipping_address must not be empty when status is %s", entities.OrderStatusShipped)
+	}
+	return nil
+}
+
 type AddOrderItemRequest struct {
-	ProductID   uuid.UUID `json:"product_id" validate:"required"`
-	ProductName string    `json:"product_name" validate:"required"`
-	Quantity    int32     `json:"quantity" validate:"required,min=1"`
-	UnitPrice   float64   `json:"unit_price" validate:"required,min=0.01"`
+	ProductID   uuid.UUID   `json:"product_id" validate:"required"`
+	ProductName string      `json:"product_name" validate:"required"`
+	Quantity    int32       `json:"quantity" validate:"required,min=1"`
+	UnitPrice   xcomp.Money `json:"unit_price" validate:"required,min=0.01"`
 }
 
 type UpdateOrderItemQuantityRequest struct {
@@ -48,10 +84,11 @@ type OrderResponse struct {
 	ID              uuid.UUID            `json:"id"`
 	CustomerID      uuid.UUID            `json:"customer_id"`
 	Status          entities.OrderStatus `json:"status"`
-	TotalAmount     float64              `json:"total_amount"`
-	ShippingCost    float64              `json:"shipping_cost"`
-	TaxAmount       float64              `json:"tax_amount"`
-	DiscountAmount  float64              `json:"discount_amount"`
+	Subtotal        xcomp.Money          `json:"subtotal"`
+	TotalAmount     xcomp.Money          `json:"total_amount"`
+	ShippingCost    xcomp.Money          `json:"shipping_cost"`
+	TaxAmount       xcomp.Money          `json:"tax_amount"`
+	DiscountAmount  xcomp.Money          `json:"discount_amount"`
 	Notes           *string              `json:"notes"`
 	ShippingAddress *string              `json:"shipping_address"`
 	BillingAddress  *string              `json:"billing_address"`
@@ -61,22 +98,16 @@ type OrderResponse struct {
 }
 
 type OrderItemResponse struct {
-	ID          uuid.UUID `json:"id"`
-	OrderID     uuid.UUID `json:"order_id"`
-	ProductID   uuid.UUID `json:"product_id"`
-	ProductName string    `json:"product_name"`
-	Quantity    int32     `json:"quantity"`
-	UnitPrice   float64   `json:"unit_price"`
-	TotalPrice  float64   `json:"total_price"`
+	ID          uuid.UUID   `json:"id"`
+	OrderID     uuid.UUID   `json:"order_id"`
+	ProductID   uuid.UUID   `json:"product_id"`
+	ProductName string      `json:"product_name"`
+	Quantity    int32       `json:"quantity"`
+	UnitPrice   xcomp.Money `json:"unit_price"`
+	TotalPrice  xcomp.Money `json:"total_price"`
 }
 
-type OrderListResponse struct {
-	Orders     []OrderResponse `json:"orders"`
-	Total      int64           `json:"total"`
-	Page       int32           `json:"page"`
-	PageSize   int32           `json:"page_size"`
-	TotalPages int32           `json:"total_pages"`
-}
+type OrderListResponse = xcomp.Page[OrderResponse]
 
 func ToOrderResponse(order *entities.Order) OrderResponse {
 	items := make([]OrderItemResponse, len(order.OrderItems))
@@ -88,10 +119,11 @@ func ToOrderResponse(order *entities.Order) OrderResponse {
 		ID:              order.ID,
 		CustomerID:      order.CustomerID,
 		Status:          order.Status,
-		TotalAmount:     order.TotalAmount,
-		ShippingCost:    order.ShippingCost,
-		TaxAmount:       order.TaxAmount,
-		DiscountAmount:  order.DiscountAmount,
+		Subtotal:        xcomp.Money(order.Subtotal()),
+		TotalAmount:     xcomp.Money(order.TotalAmount),
+		ShippingCost:    xcomp.Money(order.ShippingCost),
+		TaxAmount:       xcomp.Money(order.TaxAmount),
+		DiscountAmount:  xcomp.Money(order.DiscountAmount),
 		Notes:           order.Notes,
 		ShippingAddress: order.ShippingAddress,
 		BillingAddress:  order.BillingAddress,
@@ -108,24 +140,11 @@ func ToOrderItemResponse(item *entities.OrderItem) OrderItemResponse {
 		ProductID:   item.ProductID,
 		ProductName: item.ProductName,
 		Quantity:    item.Quantity,
-		UnitPrice:   item.UnitPrice,
-		TotalPrice:  item.TotalPrice,
+		UnitPrice:   xcomp.Money(item.UnitPrice),
+		TotalPrice:  xcomp.Money(item.TotalPrice),
 	}
 }
 
 func ToOrderListResponse(orders []*entities.Order, total int64, page, pageSize int32) OrderListResponse {
-	orderResponses := make([]OrderResponse, len(orders))
-	for i, order := range orders {
-		orderResponses[i] = ToOrderResponse(order)
-	}
-
-	totalPages := int32((total + int64(pageSize) - 1) / int64(pageSize))
-
-	return OrderListResponse{
-		Orders:     orderResponses,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}
+	return xcomp.MapPage(orders, total, page, pageSize, ToOrderResponse)
 }
@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"example/modules/order/application/dto"
@@ -16,31 +15,55 @@ import (
 )
 
 type OrderService struct {
-	orderRepo      interfaces.OrderRepository      // lowercase - manual injection
-	orderItemRepo  interfaces.OrderItemRepository  // lowercase - manual injection
-	orderCacheRepo interfaces.OrderCacheRepository // lowercase - manual injection
+	orderRepo      interfaces.OrderRepository      // lowercase - setter injection
+	orderItemRepo  interfaces.OrderItemRepository  // lowercase - setter injection
+	orderCacheRepo interfaces.OrderCacheRepository // lowercase - setter injection
 	Logger         xcomp.Logger                    `inject:"Logger"` // uppercase - auto injection
+
+	// Preconditions checked before status transitions; replace the
+	// "OrderTransitionPolicy" provider to enforce deployment-specific rules
+	TransitionPolicy interfaces.OrderTransitionPolicy `inject:"OrderTransitionPolicy"`
+
+	Events xcomp.EventBus `inject:"EventBus"`
+
+	Tx interfaces.Transactor `inject:"TxManager"`
+
+	limits entities.OrderLimits
 }
 
 func NewOrderService() *OrderService {
 	return &OrderService{}
 }
 
-// Method injection for lowercase fields
-func (s *OrderService) SetDependencies(
-	orderRepo interfaces.OrderRepository,
-	orderItemRepo interfaces.OrderItemRepository,
-	orderCacheRepo interfaces.OrderCacheRepository,
-) {
+// InjectSetters lets the container set the lowercase fields through their setters
+func (s *OrderService) InjectSetters() map[string]string {
+	return map[string]string{
+		"SetOrderRepository":      "OrderRepository",
+		"SetOrderItemRepository":  "OrderItemRepository",
+		"SetOrderCacheRepository": "OrderCacheRepository",
+	}
+}
+
+func (s *OrderService) SetOrderRepository(orderRepo interfaces.OrderRepository) {
 	s.orderRepo = orderRepo
+}
+
+func (s *OrderService) SetOrderItemRepository(orderItemRepo interfaces.OrderItemRepository) {
 	s.orderItemRepo = orderItemRepo
+}
+
+func (s *OrderService) SetOrderCacheRepository(orderCacheRepo interfaces.OrderCacheRepository) {
 	s.orderCacheRepo = orderCacheRepo
 }
 
+func (s *OrderService) SetLimits(limits entities.OrderLimits) {
+	s.limits = limits
+}
+
 func (s *OrderService) CreateOrder(ctx context.Context, req dto.CreateOrderRequest) (*dto.OrderResponse, error) {
 	s.Logger.Info("Creating order",
-		xcomp.Field("customer_id", req.CustomerID),
-		xcomp.Field("items_count", len(req.Items)))
+		xcomp.Stringer("customer_id", req.CustomerID),
+		xcomp.Int("items_count", len(req.Items)))
 
 	order := entities.NewOrder(req.CustomerID)
 	order.ShippingAddress = req.ShippingAddress
@@ -48,7 +71,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, req dto.CreateOrderReque
 	order.Notes = req.Notes
 
 	for _, itemReq := range req.Items {
-		err := order.AddItem(itemReq.ProductID, itemReq.ProductName, itemReq.Quantity, itemReq.UnitPrice)
+		err := order.AddItem(itemReq.ProductID, itemReq.ProductName, itemReq.Quantity, itemReq.UnitPrice.Float64())
 		if err != nil {
 			return nil, err
 		}
@@ -60,14 +83,36 @@ func (s *OrderService) CreateOrder(ctx context.Context, req dto.CreateOrderReque
 		return nil, err
 	}
 
-	if err := s.orderRepo.Create(ctx, order); err != nil {
+	if err := order.ValidateLimits(s.limits); err != nil {
 		return nil, err
 	}
 
-	for _, item := range order.OrderItems {
-		if err := s.orderItemRepo.Create(ctx, item); err != nil {
-			return nil, err
+	// The order and its items are stored together or not at all
+	err := s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			return err
 		}
+		for _, item := range order.OrderItems {
+			if err := s.orderItemRepo.Create(ctx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The order is stored either way; a failing subscriber must not fail the request
+	if err := s.Events.Publish(ctx, entities.OrderCreatedTopic, entities.OrderCreatedEvent{
+		OrderID:     order.ID,
+		CustomerID:  order.CustomerID,
+		TotalAmount: order.TotalAmount,
+		ItemCount:   len(order.OrderItems),
+	}); err != nil {
+		s.Logger.Warn("Failed to publish order created event",
+			xcomp.Stringer("order_id", order.ID),
+			xcomp.Err(err))
 	}
 
 	response := dto.ToOrderResponse(order)
@@ -75,28 +120,10 @@ func (s *OrderService) CreateOrder(ctx context.Context, req dto.CreateOrderReque
 }
 
 func (s *OrderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
-	s.Logger.Info("Getting order by ID", xcomp.Field("order_id", id))
-
-	order, err := s.orderCacheRepo.Get(ctx, id)
-	if err != nil {
-		order, err = s.orderRepo.GetByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-
-		items, err := s.orderItemRepo.GetByOrderID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		order.OrderItems = items
+	s.Logger.Info("Getting order by ID", xcomp.Stringer("order_id", id))
 
-		if setErr := s.orderCacheRepo.Set(ctx, order, 5*time.Minute); setErr != nil {
-			s.Logger.Warn("Failed to cache order",
-				xcomp.Field("order_id", id),
-				xcomp.Field("error", setErr))
-		}
-	} else if order == nil {
-		order, err = s.orderRepo.GetByID(ctx, id)
+	order, err := xcomp.CacheAside(ctx, s.orderCacheRepo, id, 5*time.Minute, func(ctx context.Context) (*entities.Order, error) {
+		order, err := s.orderRepo.GetByID(ctx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -106,10 +133,10 @@ func (s *OrderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*dto.Ord
 			return nil, err
 		}
 		order.OrderItems = items
-
-		if setErr := s.orderCacheRepo.Set(ctx, order, 5*time.Minute); setErr != nil {
-			log.Printf("Failed to cache order: %v", setErr)
-		}
+		return order, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	response := dto.ToOrderResponse(order)
@@ -118,11 +145,11 @@ func (s *OrderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*dto.Ord
 
 func (s *OrderService) GetOrdersByCustomerID(ctx context.Context, customerID uuid.UUID, page, pageSize int32) (*dto.OrderListResponse, error) {
 	s.Logger.Info("Getting orders for customer",
-		xcomp.Field("customer_id", customerID),
-		xcomp.Field("page", page),
-		xcomp.Field("page_size", pageSize))
+		xcomp.Stringer("customer_id", customerID),
+		xcomp.Int64("page", int64(page)),
+		xcomp.Int64("page_size", int64(pageSize)))
 
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 	orders, err := s.orderRepo.GetByCustomerID(ctx, customerID, pageSize, offset)
 	if err != nil {
 		return nil, err
@@ -145,21 +172,38 @@ func (s *OrderService) GetOrdersByCustomerID(ctx context.Context, customerID uui
 	return &response, nil
 }
 
+func (s *OrderService) HasOrders(ctx context.Context, customerID uuid.UUID) (bool, error) {
+	total, err := s.orderRepo.CountByCustomerID(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// orderItemsLoadConcurrency caps the pool connections one listing request uses for items
+const orderItemsLoadConcurrency = 4
+
 func (s *OrderService) GetAllOrders(ctx context.Context, page, pageSize int32) (*dto.OrderListResponse, error) {
-	log.Printf("OrderService: Getting all orders")
+	s.Logger.Info("Getting all orders",
+		xcomp.Int64("page", int64(page)),
+		xcomp.Int64("page_size", int64(pageSize)))
 
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 	orders, err := s.orderRepo.GetAll(ctx, pageSize, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, order := range orders {
-		items, err := s.orderItemRepo.GetByOrderID(ctx, order.ID)
-		if err != nil {
-			return nil, err
-		}
-		order.OrderItems = items
+	// One page of orders is bounded, so load their items in parallel rather than one by one
+	itemsByOrder, err := xcomp.ForEachN(ctx, orders, orderItemsLoadConcurrency,
+		func(ctx context.Context, order *entities.Order) ([]*entities.OrderItem, error) {
+			return s.orderItemRepo.GetByOrderID(ctx, order.ID)
+		})
+	if err != nil {
+		return nil, err
+	}
+	for i, order := range orders {
+		order.OrderItems = itemsByOrder[i]
 	}
 
 	total, err := s.orderRepo.Count(ctx)
@@ -172,7 +216,10 @@ func (s *OrderService) GetAllOrders(ctx context.Context, page, pageSize int32) (
 }
 
 func (s *OrderService) GetOrdersByStatus(ctx context.Context, status entities.OrderStatus, page, pageSize int32) (*dto.OrderListResponse, error) {
-	log.Printf("OrderService: Getting orders by status %s", status)
+	s.Logger.Info("Getting orders by status",
+		xcomp.String("status", string(status)),
+		xcomp.Int64("page", int64(page)),
+		xcomp.Int64("page_size", int64(pageSize)))
 
 	// Check if dependencies are properly injected
 	if s.orderRepo == nil {
@@ -182,7 +229,7 @@ func (s *OrderService) GetOrdersByStatus(ctx context.Context, status entities.Or
 		return nil, fmt.Errorf("orderItemRepo is not injected")
 	}
 
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 	orders, err := s.orderRepo.GetByStatus(ctx, status, pageSize, offset)
 	if err != nil {
 		return nil, err
@@ -209,44 +256,20 @@ func (s *OrderService) GetOrdersByStatus(ctx context.Context, status entities.Or
 }
 
 func (s *OrderService) UpdateOrder(ctx context.Context, id uuid.UUID, req dto.UpdateOrderRequest) (*dto.OrderResponse, error) {
-	s.Logger.Info("Updating order", xcomp.Field("order_id", id))
+	s.Logger.Info("Updating order", xcomp.Stringer("order_id", id))
 
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Status != nil {
-		order.Status = *req.Status
-	}
-	if req.ShippingCost != nil {
-		order.ShippingCost = *req.ShippingCost
-	}
-	if req.TaxAmount != nil {
-		order.TaxAmount = *req.TaxAmount
-	}
-	if req.DiscountAmount != nil {
-		order.DiscountAmount = *req.DiscountAmount
-	}
-	if req.ShippingAddress != nil {
-		order.ShippingAddress = req.ShippingAddress
-	}
-	if req.BillingAddress != nil {
-		order.BillingAddress = req.BillingAddress
-	}
-	if req.Notes != nil {
-		order.Notes = req.Notes
-	}
-
-	order.CalculateTotal()
-
-	if err := order.Validate(); err != nil {
-		return nil, err
-	}
-
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return nil, err
-	}
+	xcomp.ApplyIfSet(&order.Status, req.Status)
+	xcomp.ApplyIfSetFunc(&order.ShippingCost, req.ShippingCost, xcomp.Money.Float64)
+	xcomp.ApplyIfSetFunc(&order.TaxAmount, req.TaxAmount, xcomp.Money.Float64)
+	xcomp.ApplyIfSetFunc(&order.DiscountAmount, req.DiscountAmount, xcomp.Money.Float64)
+	xcomp.ApplyIfSetFunc(&order.ShippingAddress, req.ShippingAddress, xcomp.Ptr[string])
+	xcomp.ApplyIfSetFunc(&order.BillingAddress, req.BillingAddress, xcomp.Ptr[string])
+	xcomp.ApplyIfSetFunc(&order.Notes, req.Notes, xcomp.Ptr[string])
 
 	items, err := s.orderItemRepo.GetByOrderID(ctx, id)
 	if err != nil {
@@ -254,19 +277,17 @@ func (s *OrderService) UpdateOrder(ctx context.Context, id uuid.UUID, req dto.Up
 	}
 	order.OrderItems = items
 
-	response := dto.ToOrderResponse(order)
-	return &response, nil
-}
+	if order.DiscountAmount > order.Subtotal()+order.ShippingCost+order.TaxAmount {
+		return nil, entities.ErrOrderDiscountExceedsTotal
+	}
 
-func (s *OrderService) ConfirmOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Confirming order %s", id)
+	order.CalculateTotal()
 
-	order, err := s.orderRepo.GetByID(ctx, id)
-	if err != nil {
+	if err := order.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := order.ConfirmOrder(); err != nil {
+	if err := order.ValidateLimits(s.limits); err != nil {
 		return nil, err
 	}
 
@@ -274,29 +295,42 @@ func (s *OrderService) ConfirmOrder(ctx context.Context, id uuid.UUID) (*dto.Ord
 		return nil, err
 	}
 
-	items, err := s.orderItemRepo.GetByOrderID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	order.OrderItems = items
+	s.invalidateOrder(ctx, id)
 
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
+func (s *OrderService) ConfirmOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
+	s.Logger.Info("Confirming order", xcomp.Stringer("order_id", id))
+	return s.transitionOrder(ctx, id, s.transitionPolicy().CanConfirm, (*entities.Order).ConfirmOrder)
+}
+
 func (s *OrderService) ShipOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Shipping order %s", id)
+	s.Logger.Info("Shipping order", xcomp.Stringer("order_id", id))
+	return s.transitionOrder(ctx, id, s.transitionPolicy().CanShip, (*entities.Order).ShipOrder)
+}
 
-	order, err := s.orderRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
+func (s *OrderService) DeliverOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
+	s.Logger.Info("Delivering order", xcomp.Stringer("order_id", id))
+	return s.transitionOrder(ctx, id, s.transitionPolicy().CanDeliver, (*entities.Order).DeliverOrder)
+}
 
-	if err := order.ShipOrder(); err != nil {
-		return nil, err
-	}
+func (s *OrderService) CancelOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
+	s.Logger.Info("Cancelling order", xcomp.Stringer("order_id", id))
+	return s.transitionOrder(ctx, id, s.transitionPolicy().CanCancel, (*entities.Order).CancelOrder)
+}
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
+// transitionOrder loads the order with its items, runs the policy precondition and
+// then the state machine transition, and persists the result
+func (s *OrderService) transitionOrder(
+	ctx context.Context,
+	id uuid.UUID,
+	precondition func(context.Context, *entities.Order) error,
+	transition func(*entities.Order) error,
+) (*dto.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
@@ -306,19 +340,11 @@ func (s *OrderService) ShipOrder(ctx context.Context, id uuid.UUID) (*dto.OrderR
 	}
 	order.OrderItems = items
 
-	response := dto.ToOrderResponse(order)
-	return &response, nil
-}
-
-func (s *OrderService) DeliverOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Delivering order %s", id)
-
-	order, err := s.orderRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+	if err := precondition(ctx, order); err != nil {
+		return nil, entities.ErrOrderTransitionRejected.WithCause(err)
 	}
 
-	if err := order.DeliverOrder(); err != nil {
+	if err := transition(order); err != nil {
 		return nil, err
 	}
 
@@ -326,71 +352,76 @@ func (s *OrderService) DeliverOrder(ctx context.Context, id uuid.UUID) (*dto.Ord
 		return nil, err
 	}
 
-	items, err := s.orderItemRepo.GetByOrderID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	order.OrderItems = items
+	s.invalidateOrder(ctx, id)
 
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
-func (s *OrderService) CancelOrder(ctx context.Context, id uuid.UUID) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Cancelling order %s", id)
-
-	order, err := s.orderRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+func (s *OrderService) transitionPolicy() interfaces.OrderTransitionPolicy {
+	if s.TransitionPolicy == nil {
+		return &DefaultOrderTransitionPolicy{}
 	}
+	return s.TransitionPolicy
+}
 
-	if err := order.CancelOrder(); err != nil {
-		return nil, err
-	}
+func (s *OrderService) AddOrderItem(ctx context.Context, orderID uuid.UUID, req dto.AddOrderItemRequest) (*dto.OrderResponse, error) {
+	s.Logger.Info("Adding item to order",
+		xcomp.Stringer("order_id", orderID),
+		xcomp.Stringer("product_id", req.ProductID))
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
 		return nil, err
 	}
 
-	items, err := s.orderItemRepo.GetByOrderID(ctx, id)
+	// The limits apply to the whole order, so validate against the stored items too
+	items, err := s.orderItemRepo.GetByOrderID(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
 	order.OrderItems = items
 
-	response := dto.ToOrderResponse(order)
-	return &response, nil
-}
-
-func (s *OrderService) AddOrderItem(ctx context.Context, orderID uuid.UUID, req dto.AddOrderItemRequest) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Adding item to order %s", orderID)
-
-	order, err := s.orderRepo.GetByID(ctx, orderID)
-	if err != nil {
+	if err := order.AddItem(req.ProductID, req.ProductName, req.Quantity, req.UnitPrice.Float64()); err != nil {
 		return nil, err
 	}
 
-	if err := order.AddItem(req.ProductID, req.ProductName, req.Quantity, req.UnitPrice); err != nil {
+	order.CalculateTotal()
+
+	if err := order.ValidateLimits(s.limits); err != nil {
 		return nil, err
 	}
 
-	order.CalculateTotal()
-
 	if err := s.orderRepo.Update(ctx, order); err != nil {
 		return nil, err
 	}
 
-	newItem := order.OrderItems[len(order.OrderItems)-1]
-	if err := s.orderItemRepo.Create(ctx, newItem); err != nil {
-		return nil, err
+	// AddItem merges a product already on the order into its existing line
+	if len(order.OrderItems) > len(items) {
+		if err := s.orderItemRepo.Create(ctx, order.OrderItems[len(order.OrderItems)-1]); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, item := range order.OrderItems {
+			if item.ProductID == req.ProductID {
+				if err := s.orderItemRepo.Update(ctx, item); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
 	}
 
+	s.invalidateOrder(ctx, orderID)
+
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
 func (s *OrderService) UpdateOrderItemQuantity(ctx context.Context, orderID, productID uuid.UUID, req dto.UpdateOrderItemQuantityRequest) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Updating item quantity in order %s", orderID)
+	s.Logger.Info("Updating item quantity",
+		xcomp.Stringer("order_id", orderID),
+		xcomp.Stringer("product_id", productID))
 
 	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
@@ -409,6 +440,10 @@ func (s *OrderService) UpdateOrderItemQuantity(ctx context.Context, orderID, pro
 
 	order.CalculateTotal()
 
+	if err := order.ValidateLimits(s.limits); err != nil {
+		return nil, err
+	}
+
 	if err := s.orderRepo.Update(ctx, order); err != nil {
 		return nil, err
 	}
@@ -422,12 +457,16 @@ func (s *OrderService) UpdateOrderItemQuantity(ctx context.Context, orderID, pro
 		}
 	}
 
+	s.invalidateOrder(ctx, orderID)
+
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
 func (s *OrderService) RemoveOrderItem(ctx context.Context, orderID, productID uuid.UUID) (*dto.OrderResponse, error) {
-	log.Printf("OrderService: Removing item from order %s", orderID)
+	s.Logger.Info("Removing item from order",
+		xcomp.Stringer("order_id", orderID),
+		xcomp.Stringer("product_id", productID))
 
 	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
@@ -466,16 +505,33 @@ func (s *OrderService) RemoveOrderItem(ctx context.Context, orderID, productID u
 		return nil, err
 	}
 
+	s.invalidateOrder(ctx, orderID)
+
 	response := dto.ToOrderResponse(order)
 	return &response, nil
 }
 
 func (s *OrderService) DeleteOrder(ctx context.Context, id uuid.UUID) error {
-	log.Printf("OrderService: Deleting order %s", id)
+	s.Logger.Info("Deleting order", xcomp.Stringer("order_id", id))
 
 	if err := s.orderItemRepo.DeleteByOrderID(ctx, id); err != nil {
 		return err
 	}
 
-	return s.orderRepo.Delete(ctx, id)
+	if err := s.orderRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidateOrder(ctx, id)
+	return nil
+}
+
+// invalidateOrder drops the cached copy after a write so GetOrderByID reloads it.
+// A failed delete is only logged; the entry still expires with its TTL.
+func (s *OrderService) invalidateOrder(ctx context.Context, id uuid.UUID) {
+	if err := s.orderCacheRepo.Delete(ctx, id); err != nil {
+		s.Logger.Warn("Failed to invalidate cached order",
+			xcomp.Stringer("order_id", id),
+			xcomp.Err(err))
+	}
 }
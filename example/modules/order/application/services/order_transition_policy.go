@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"example/modules/order/domain/entities"
+	"example/modules/order/domain/interfaces"
+)
+
+// DefaultOrderTransitionPolicy allows every transition the state machine allows
+type DefaultOrderTransitionPolicy struct{}
+
+func (p *DefaultOrderTransitionPolicy) GetServiceName() string {
+	return "OrderTransitionPolicy"
+}
+
+func (p *DefaultOrderTransitionPolicy) CanConfirm(ctx context.Context, order *entities.Order) error {
+	return nil
+}
+
+func (p *DefaultOrderTransitionPolicy) CanShip(ctx context.Context, order *entities.Order) error {
+	return nil
+}
+
+func (p *DefaultOrderTransitionPolicy) CanDeliver(ctx context.Context, order *entities.Order) error {
+	return nil
+}
+
+func (p *DefaultOrderTransitionPolicy) CanCancel(ctx context.Context, order *entities.Order) error {
+	return nil
+}
+
+var _ interfaces.OrderTransitionPolicy = (*DefaultOrderTransitionPolicy)(nil)
@@ -1,16 +1,21 @@
 package entities
 
-import "errors"
+import "xcomp"
 
+// Each error carries the HTTP status it is reported with, see xcomp.AppError
 var (
-	ErrOrderNotFound            = errors.New("order not found")
-	ErrOrderItemNotFound        = errors.New("order item not found")
-	ErrOrderAlreadyCancelled    = errors.New("order is already cancelled")
-	ErrOrderAlreadyCompleted    = errors.New("order is already completed")
-	ErrOrderCannotBeModified    = errors.New("order cannot be modified in current status")
-	ErrInvalidOrderStatus       = errors.New("invalid order status")
-	ErrOrderItemQuantityInvalid = errors.New("order item quantity must be greater than 0")
-	ErrOrderItemPriceInvalid    = errors.New("order item price must be greater than 0")
-	ErrOrderTotalMismatch       = errors.New("order total does not match sum of items")
-	ErrEmptyOrder               = errors.New("order must contain at least one item")
+	ErrOrderNotFound             = xcomp.NewNotFound("order not found")
+	ErrOrderItemNotFound         = xcomp.NewNotFound("order item not found")
+	ErrOrderAlreadyCancelled     = xcomp.NewConflict("order is already cancelled")
+	ErrOrderAlreadyCompleted     = xcomp.NewConflict("order is already completed")
+	ErrOrderCannotBeModified     = xcomp.NewConflict("order cannot be modified in current status")
+	ErrInvalidOrderStatus        = xcomp.NewValidation("invalid order status")
+	ErrOrderItemQuantityInvalid  = xcomp.NewValidation("order item quantity must be greater than 0")
+	ErrOrderItemPriceInvalid     = xcomp.NewValidation("order item price must be greater than 0")
+	ErrOrderTotalMismatch        = xcomp.NewValidation("order total does not match sum of items")
+	ErrEmptyOrder                = xcomp.NewValidation("order must contain at least one item")
+	ErrOrderTooManyItems         = xcomp.NewValidation("order exceeds the maximum number of items")
+	ErrOrderTotalExceedsLimit    = xcomp.NewValidation("order total exceeds the maximum allowed amount")
+	ErrOrderTransitionRejected   = xcomp.NewConflict("order status transition rejected")
+	ErrOrderDiscountExceedsTotal = xcomp.NewValidation("order discount exceeds the order total")
 )
@@ -0,0 +1,13 @@
+package entities
+
+import "github.com/google/uuid"
+
+// OrderCreatedTopic is published on the EventBus after an order and its items are stored
+const OrderCreatedTopic = "order.created"
+
+type OrderCreatedEvent struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	CustomerID  uuid.UUID `json:"customer_id"`
+	TotalAmount float64   `json:"total_amount"`
+	ItemCount   int       `json:"item_count"`
+}
@@ -32,6 +32,12 @@ type Order struct {
 	UpdatedAt       time.Time    `json:"updated_at"`
 }
 
+// OrderLimits caps order size; a zero value disables the corresponding check
+type OrderLimits struct {
+	MaxItems int
+	MaxTotal float64
+}
+
 func NewOrder(customerID uuid.UUID) *Order {
 	return &Order{
 		ID:         uuid.New(),
@@ -72,6 +78,18 @@ func (o *Order) Validate() error {
 	return nil
 }
 
+func (o *Order) ValidateLimits(limits OrderLimits) error {
+	if limits.MaxItems > 0 && len(o.OrderItems) > limits.MaxItems {
+		return ErrOrderTooManyItems.Withf("%d items, limit is %d", len(o.OrderItems), limits.MaxItems)
+	}
+
+	if limits.MaxTotal > 0 && o.TotalAmount > limits.MaxTotal {
+		return ErrOrderTotalExceedsLimit.Withf("total %.2f, limit is %.2f", o.TotalAmount, limits.MaxTotal)
+	}
+
+	return nil
+}
+
 func (o *Order) AddItem(productID uuid.UUID, productName string, quantity int32, unitPrice float64) error {
 	if quantity <= 0 {
 		return ErrOrderItemQuantityInvalid
@@ -196,6 +214,11 @@ func (o *Order) CalculateTotal() {
 	o.UpdatedAt = time.Now()
 }
 
+// Subtotal is the sum of item totals before shipping, tax and discount
+func (o *Order) Subtotal() float64 {
+	return o.calculateItemsTotal()
+}
+
 func (o *Order) calculateItemsTotal() float64 {
 	total := 0.0
 	for _, item := range o.OrderItems {
@@ -24,4 +24,5 @@ type OrderService interface {
 	UpdateOrderItemQuantity(ctx context.Context, orderID, productID uuid.UUID, req dto.UpdateOrderItemQuantityRequest) (*dto.OrderResponse, error)
 	RemoveOrderItem(ctx context.Context, orderID, productID uuid.UUID) (*dto.OrderResponse, error)
 	DeleteOrder(ctx context.Context, id uuid.UUID) error
+	HasOrders(ctx context.Context, customerID uuid.UUID) (bool, error)
 }
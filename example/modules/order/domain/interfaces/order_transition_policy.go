@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"example/modules/order/domain/entities"
+)
+
+// OrderTransitionPolicy adds deployment-specific preconditions on top of the order
+// state machine, e.g. refusing to ship until every item is in stock. Each check sees
+// the order with its items loaded and blocks the transition by returning an error.
+type OrderTransitionPolicy interface {
+	CanConfirm(ctx context.Context, order *entities.Order) error
+	CanShip(ctx context.Context, order *entities.Order) error
+	CanDeliver(ctx context.Context, order *entities.Order) error
+	CanCancel(ctx context.Context, order *entities.Order) error
+}
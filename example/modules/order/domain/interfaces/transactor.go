@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// Transactor runs fn in one database transaction; repositories called with
+// the context passed to fn take part in it
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -36,25 +36,23 @@ func (q *Queries) CountOrdersByCustomerID(ctx context.Context, customerID pgtype
 const createOrder = `-- name: CreateOrder :one
 INSERT INTO orders (
     id, customer_id, status, total_amount, shipping_cost, tax_amount,
-    discount_amount, notes, shipping_address, billing_address, created_at, updated_at
+    discount_amount, notes, shipping_address, billing_address
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING id, customer_id, status, total_amount, shipping_cost, tax_amount, discount_amount, notes, shipping_address, billing_address, created_at, updated_at
 `
 
 type CreateOrderParams struct {
-	ID              pgtype.UUID        `db:"id"`
-	CustomerID      pgtype.UUID        `db:"customer_id"`
-	Status          string             `db:"status"`
-	TotalAmount     pgtype.Numeric     `db:"total_amount"`
-	ShippingCost    pgtype.Numeric     `db:"shipping_cost"`
-	TaxAmount       pgtype.Numeric     `db:"tax_amount"`
-	DiscountAmount  pgtype.Numeric     `db:"discount_amount"`
-	Notes           *string            `db:"notes"`
-	ShippingAddress *string            `db:"shipping_address"`
-	BillingAddress  *string            `db:"billing_address"`
-	CreatedAt       pgtype.Timestamptz `db:"created_at"`
-	UpdatedAt       pgtype.Timestamptz `db:"updated_at"`
+	ID              pgtype.UUID    `db:"id"`
+	CustomerID      pgtype.UUID    `db:"customer_id"`
+	Status          string         `db:"status"`
+	TotalAmount     pgtype.Numeric `db:"total_amount"`
+	ShippingCost    pgtype.Numeric `db:"shipping_cost"`
+	TaxAmount       pgtype.Numeric `db:"tax_amount"`
+	DiscountAmount  pgtype.Numeric `db:"discount_amount"`
+	Notes           *string        `db:"notes"`
+	ShippingAddress *string        `db:"shipping_address"`
+	BillingAddress  *string        `db:"billing_address"`
 }
 
 // Order queries
@@ -70,8 +68,6 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (*Orde
 		arg.Notes,
 		arg.ShippingAddress,
 		arg.BillingAddress,
-		arg.CreatedAt,
-		arg.UpdatedAt,
 	)
 	var i Order
 	err := row.Scan(
@@ -375,22 +371,21 @@ const updateOrder = `-- name: UpdateOrder :one
 UPDATE orders
 SET status = $2, total_amount = $3, shipping_cost = $4, tax_amount = $5,
     discount_amount = $6, notes = $7, shipping_address = $8, billing_address = $9,
-    updated_at = $10
+    updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING id, customer_id, status, total_amount, shipping_cost, tax_amount, discount_amount, notes, shipping_address, billing_address, created_at, updated_at
 `
 
 type UpdateOrderParams struct {
-	ID              pgtype.UUID        `db:"id"`
-	Status          string             `db:"status"`
-	TotalAmount     pgtype.Numeric     `db:"total_amount"`
-	ShippingCost    pgtype.Numeric     `db:"shipping_cost"`
-	TaxAmount       pgtype.Numeric     `db:"tax_amount"`
-	DiscountAmount  pgtype.Numeric     `db:"discount_amount"`
-	Notes           *string            `db:"notes"`
-	ShippingAddress *string            `db:"shipping_address"`
-	BillingAddress  *string            `db:"billing_address"`
-	UpdatedAt       pgtype.Timestamptz `db:"updated_at"`
+	ID              pgtype.UUID    `db:"id"`
+	Status          string         `db:"status"`
+	TotalAmount     pgtype.Numeric `db:"total_amount"`
+	ShippingCost    pgtype.Numeric `db:"shipping_cost"`
+	TaxAmount       pgtype.Numeric `db:"tax_amount"`
+	DiscountAmount  pgtype.Numeric `db:"discount_amount"`
+	Notes           *string        `db:"notes"`
+	ShippingAddress *string        `db:"shipping_address"`
+	BillingAddress  *string        `db:"billing_address"`
 }
 
 func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (*Order, error) {
@@ -404,7 +399,6 @@ func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (*Orde
 		arg.Notes,
 		arg.ShippingAddress,
 		arg.BillingAddress,
-		arg.UpdatedAt,
 	)
 	var i Order
 	err := row.Scan(
@@ -2,9 +2,9 @@
 -- name: CreateOrder :one
 INSERT INTO orders (
     id, customer_id, status, total_amount, shipping_cost, tax_amount,
-    discount_amount, notes, shipping_address, billing_address, created_at, updated_at
+    discount_amount, notes, shipping_address, billing_address
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: GetOrderByID :one
@@ -31,7 +31,7 @@ LIMIT $1 OFFSET $2;
 UPDATE orders
 SET status = $2, total_amount = $3, shipping_cost = $4, tax_amount = $5,
     discount_amount = $6, notes = $7, shipping_address = $8, billing_address = $9,
-    updated_at = $10
+    updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
@@ -2,19 +2,22 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"example/modules/order/domain/entities"
 	"example/modules/order/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type OrderCacheRepositoryImpl struct {
-	RedisClient *redis.Client `inject:"RedisClient"`
+	RedisClient  *redis.Client       `inject:"RedisClient"`
+	CacheMetrics *xcomp.CacheMetrics `inject:"CacheMetrics"`
+	Serializer   xcomp.Serializer    `inject:"Serializer"`
 }
 
 func (r *OrderCacheRepositoryImpl) GetServiceName() string {
@@ -22,27 +25,38 @@ func (r *OrderCacheRepositoryImpl) GetServiceName() string {
 }
 
 func (r *OrderCacheRepositoryImpl) Get(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
-	key := fmt.Sprintf("order:%s", id.String())
+	if r.RedisClient == nil {
+		r.CacheMetrics.Miss("order")
+		return nil, nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("order:%s", id.String()))
 	val, err := r.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
+			r.CacheMetrics.Miss("order")
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get order from cache: %w", err)
 	}
 
 	var order entities.Order
-	if err := json.Unmarshal([]byte(val), &order); err != nil {
+	if err := r.Serializer.Unmarshal([]byte(val), &order); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal order: %w", err)
 	}
 
+	r.CacheMetrics.Hit("order")
 	return &order, nil
 }
 
 func (r *OrderCacheRepositoryImpl) Set(ctx context.Context, order *entities.Order, expiration time.Duration) error {
-	key := fmt.Sprintf("order:%s", order.ID.String())
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("order:%s", order.ID.String()))
 
-	data, err := json.Marshal(order)
+	data, err := r.Serializer.Marshal(order)
 	if err != nil {
 		return fmt.Errorf("failed to marshal order: %w", err)
 	}
@@ -55,7 +69,11 @@ func (r *OrderCacheRepositoryImpl) Set(ctx context.Context, order *entities.Orde
 }
 
 func (r *OrderCacheRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	key := fmt.Sprintf("order:%s", id.String())
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("order:%s", id.String()))
 	if err := r.RedisClient.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete order from cache: %w", err)
 	}
@@ -63,27 +81,38 @@ func (r *OrderCacheRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) err
 }
 
 func (r *OrderCacheRepositoryImpl) GetByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*entities.Order, error) {
-	key := fmt.Sprintf("orders:customer:%s", customerID.String())
+	if r.RedisClient == nil {
+		r.CacheMetrics.Miss("customer_orders")
+		return nil, nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("orders:customer:%s", customerID.String()))
 	val, err := r.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
+			r.CacheMetrics.Miss("customer_orders")
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get customer orders from cache: %w", err)
 	}
 
 	var orders []*entities.Order
-	if err := json.Unmarshal([]byte(val), &orders); err != nil {
+	if err := r.Serializer.Unmarshal([]byte(val), &orders); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal customer orders: %w", err)
 	}
 
+	r.CacheMetrics.Hit("customer_orders")
 	return orders, nil
 }
 
 func (r *OrderCacheRepositoryImpl) SetByCustomerID(ctx context.Context, customerID uuid.UUID, orders []*entities.Order, expiration time.Duration) error {
-	key := fmt.Sprintf("orders:customer:%s", customerID.String())
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("orders:customer:%s", customerID.String()))
 
-	data, err := json.Marshal(orders)
+	data, err := r.Serializer.Marshal(orders)
 	if err != nil {
 		return fmt.Errorf("failed to marshal customer orders: %w", err)
 	}
@@ -96,7 +125,11 @@ func (r *OrderCacheRepositoryImpl) SetByCustomerID(ctx context.Context, customer
 }
 
 func (r *OrderCacheRepositoryImpl) DeleteByCustomerID(ctx context.Context, customerID uuid.UUID) error {
-	key := fmt.Sprintf("orders:customer:%s", customerID.String())
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	key := xcomp.TenantCacheKey(ctx, fmt.Sprintf("orders:customer:%s", customerID.String()))
 	if err := r.RedisClient.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete customer orders from cache: %w", err)
 	}
@@ -104,14 +137,25 @@ func (r *OrderCacheRepositoryImpl) DeleteByCustomerID(ctx context.Context, custo
 }
 
 func (r *OrderCacheRepositoryImpl) Clear(ctx context.Context) error {
-	iter := r.RedisClient.Scan(ctx, 0, "order:*", 0).Iterator()
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	iter := r.RedisClient.Scan(ctx, 0, xcomp.TenantCacheKey(ctx, "order:*"), 0).Iterator()
 	var keysToDelete []string
 
 	for iter.Next(ctx) {
+		// Stop promptly on client disconnect or shutdown instead of scanning the whole keyspace
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		keysToDelete = append(keysToDelete, iter.Val())
 	}
 
 	if err := iter.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		return fmt.Errorf("failed to scan cache keys: %w", err)
 	}
 
@@ -2,25 +2,32 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
+	"strconv"
 
+	"example/infrastructure/database"
 	"example/modules/order/domain/entities"
 	"example/modules/order/infrastructure/query/gen"
 
+	"xcomp"
+
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OrderRepositoryImpl struct {
-	DB *pgxpool.Pool `inject:"DatabaseConnection"`
-	Q  *gen.Queries
+	DB      *pgxpool.Pool `inject:"DatabaseConnection"`
+	queries xcomp.Lazy[*gen.Queries]
 }
 
 type OrderItemRepositoryImpl struct {
-	DB *pgxpool.Pool `inject:"DatabaseConnection"`
-	Q  *gen.Queries
+	DB      *pgxpool.Pool `inject:"DatabaseConnection"`
+	queries xcomp.Lazy[*gen.Queries]
 }
 
 func (r *OrderRepositoryImpl) GetServiceName() string {
@@ -31,55 +38,77 @@ func (r *OrderItemRepositoryImpl) GetServiceName() string {
 	return "OrderItemRepository"
 }
 
-func (r *OrderRepositoryImpl) ensureQueries() {
-	if r.Q == nil {
-		r.Q = gen.New(r.DB)
+// q joins the transaction carried by ctx, if any
+func (r *OrderRepositoryImpl) q(ctx context.Context) *gen.Queries {
+	queries := r.queries.Get(func() *gen.Queries {
+		return gen.New(r.DB)
+	})
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return queries.WithTx(tx)
 	}
+	return queries
 }
 
-func (r *OrderItemRepositoryImpl) ensureQueries() {
-	if r.Q == nil {
-		r.Q = gen.New(r.DB)
+// q joins the transaction carried by ctx, if any
+func (r *OrderItemRepositoryImpl) q(ctx context.Context) *gen.Queries {
+	queries := r.queries.Get(func() *gen.Queries {
+		return gen.New(r.DB)
+	})
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return queries.WithTx(tx)
 	}
+	return queries
 }
 
 func (r *OrderRepositoryImpl) Create(ctx context.Context, order *entities.Order) error {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Creating order %s", order.ID)
 
+	amounts, err := convertOrderAmounts(order)
+	if err != nil {
+		return xcomp.WrapOp("OrderRepository.Create", "order", order.ID, err)
+	}
+
 	params := gen.CreateOrderParams{
 		ID:              uuidToPgUUID(order.ID),
 		CustomerID:      uuidToPgUUID(order.CustomerID),
 		Status:          string(order.Status),
-		TotalAmount:     float64ToNumeric(order.TotalAmount),
-		ShippingCost:    float64ToNumeric(order.ShippingCost),
-		TaxAmount:       float64ToNumeric(order.TaxAmount),
-		DiscountAmount:  float64ToNumeric(order.DiscountAmount),
+		TotalAmount:     amounts.total,
+		ShippingCost:    amounts.shipping,
+		TaxAmount:       amounts.tax,
+		DiscountAmount:  amounts.discount,
 		Notes:           order.Notes,
 		ShippingAddress: order.ShippingAddress,
 		BillingAddress:  order.BillingAddress,
-		CreatedAt:       pgtype.Timestamptz{Time: order.CreatedAt, Valid: true},
-		UpdatedAt:       pgtype.Timestamptz{Time: order.UpdatedAt, Valid: true},
 	}
 
-	_, err := r.Q.CreateOrder(ctx, params)
-	return err
+	row, err := r.q(ctx).CreateOrder(ctx, params)
+	if err != nil {
+		return xcomp.WrapOp("OrderRepository.Create", "order", order.ID, err)
+	}
+
+	applyStoredTimestamps(order, row)
+	return nil
 }
 
 func (r *OrderRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Getting order by ID %s", id)
 
-	row, err := r.Q.GetOrderByID(ctx, uuidToPgUUID(id))
+	row, err := r.q(ctx).GetOrderByID(ctx, uuidToPgUUID(id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, xcomp.WrapOp("OrderRepository.GetByID", "order", id, entities.ErrOrderNotFound)
+	}
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderRepository.GetByID", "order", id, err)
 	}
 
-	return convertOrderFromDB(*row), nil
+	order, err := convertOrderFromDB(*row)
+	if err != nil {
+		return nil, xcomp.WrapOp("OrderRepository.GetByID", "order", id, err)
+	}
+	return order, nil
 }
 
 func (r *OrderRepositoryImpl) GetByCustomerID(ctx context.Context, customerID uuid.UUID, limit, offset int32) ([]*entities.Order, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Getting orders for customer %s", customerID)
 
 	params := gen.GetOrdersByCustomerIDParams{
@@ -88,49 +117,70 @@ func (r *OrderRepositoryImpl) GetByCustomerID(ctx context.Context, customerID uu
 		Offset:     offset,
 	}
 
-	rows, err := r.Q.GetOrdersByCustomerID(ctx, params)
+	rows, err := r.q(ctx).GetOrdersByCustomerID(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderRepository.GetByCustomerID", "order", customerID, err)
 	}
 
 	orders := make([]*entities.Order, len(rows))
 	for i, row := range rows {
-		orders[i] = convertOrderFromDB(*row)
+		order, err := convertOrderFromDB(*row)
+		if err != nil {
+			return nil, xcomp.WrapOp("OrderRepository.GetByCustomerID", "order", pgUUIDToUUID(row.ID), err)
+		}
+		orders[i] = order
 	}
 
 	return orders, nil
 }
 
 func (r *OrderRepositoryImpl) Update(ctx context.Context, order *entities.Order) error {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Updating order %s", order.ID)
 
+	amounts, err := convertOrderAmounts(order)
+	if err != nil {
+		return xcomp.WrapOp("OrderRepository.Update", "order", order.ID, err)
+	}
+
 	params := gen.UpdateOrderParams{
 		ID:              uuidToPgUUID(order.ID),
 		Status:          string(order.Status),
-		TotalAmount:     float64ToNumeric(order.TotalAmount),
-		ShippingCost:    float64ToNumeric(order.ShippingCost),
-		TaxAmount:       float64ToNumeric(order.TaxAmount),
-		DiscountAmount:  float64ToNumeric(order.DiscountAmount),
+		TotalAmount:     amounts.total,
+		ShippingCost:    amounts.shipping,
+		TaxAmount:       amounts.tax,
+		DiscountAmount:  amounts.discount,
 		Notes:           order.Notes,
 		ShippingAddress: order.ShippingAddress,
 		BillingAddress:  order.BillingAddress,
-		UpdatedAt:       pgtype.Timestamptz{Time: order.UpdatedAt, Valid: true},
 	}
 
-	_, err := r.Q.UpdateOrder(ctx, params)
-	return err
+	row, err := r.q(ctx).UpdateOrder(ctx, params)
+	if err != nil {
+		return xcomp.WrapOp("OrderRepository.Update", "order", order.ID, err)
+	}
+
+	applyStoredTimestamps(order, row)
+	return nil
+}
+
+// applyStoredTimestamps copies the database-assigned timestamps back onto order, so
+// callers see what was stored rather than the application clock
+func applyStoredTimestamps(order *entities.Order, row *gen.Order) {
+	if row.CreatedAt.Valid {
+		order.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		order.UpdatedAt = row.UpdatedAt.Time
+	}
 }
 
 func (r *OrderRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Deleting order %s", id)
 
-	return r.Q.DeleteOrder(ctx, uuidToPgUUID(id))
+	return xcomp.WrapOp("OrderRepository.Delete", "order", id, r.q(ctx).DeleteOrder(ctx, uuidToPgUUID(id)))
 }
 
 func (r *OrderRepositoryImpl) GetByStatus(ctx context.Context, status entities.OrderStatus, limit, offset int32) ([]*entities.Order, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Getting orders by status %s", status)
 
 	params := gen.GetOrdersByStatusParams{
@@ -139,21 +189,24 @@ func (r *OrderRepositoryImpl) GetByStatus(ctx context.Context, status entities.O
 		Offset: offset,
 	}
 
-	rows, err := r.Q.GetOrdersByStatus(ctx, params)
+	rows, err := r.q(ctx).GetOrdersByStatus(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderRepository.GetByStatus", "order", status, err)
 	}
 
 	orders := make([]*entities.Order, len(rows))
 	for i, row := range rows {
-		orders[i] = convertOrderFromDB(*row)
+		order, err := convertOrderFromDB(*row)
+		if err != nil {
+			return nil, xcomp.WrapOp("OrderRepository.GetByStatus", "order", pgUUIDToUUID(row.ID), err)
+		}
+		orders[i] = order
 	}
 
 	return orders, nil
 }
 
 func (r *OrderRepositoryImpl) GetAll(ctx context.Context, limit, offset int32) ([]*entities.Order, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Getting all orders")
 
 	params := gen.GetAllOrdersParams{
@@ -161,111 +214,135 @@ func (r *OrderRepositoryImpl) GetAll(ctx context.Context, limit, offset int32) (
 		Offset: offset,
 	}
 
-	rows, err := r.Q.GetAllOrders(ctx, params)
+	rows, err := r.q(ctx).GetAllOrders(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderRepository.GetAll", "order", nil, err)
 	}
 
 	orders := make([]*entities.Order, len(rows))
 	for i, row := range rows {
-		orders[i] = convertOrderFromDB(*row)
+		order, err := convertOrderFromDB(*row)
+		if err != nil {
+			return nil, xcomp.WrapOp("OrderRepository.GetAll", "order", pgUUIDToUUID(row.ID), err)
+		}
+		orders[i] = order
 	}
 
 	return orders, nil
 }
 
 func (r *OrderRepositoryImpl) Count(ctx context.Context) (int64, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Counting orders")
 
-	return r.Q.CountOrders(ctx)
+	count, err := r.q(ctx).CountOrders(ctx)
+	if err != nil {
+		return 0, xcomp.WrapOp("OrderRepository.Count", "order", nil, err)
+	}
+	return count, nil
 }
 
 func (r *OrderRepositoryImpl) CountByCustomerID(ctx context.Context, customerID uuid.UUID) (int64, error) {
-	r.ensureQueries()
 	log.Printf("OrderRepository: Counting orders for customer %s", customerID)
 
-	return r.Q.CountOrdersByCustomerID(ctx, uuidToPgUUID(customerID))
+	count, err := r.q(ctx).CountOrdersByCustomerID(ctx, uuidToPgUUID(customerID))
+	if err != nil {
+		return 0, xcomp.WrapOp("OrderRepository.CountByCustomerID", "order", customerID, err)
+	}
+	return count, nil
 }
 
 func (r *OrderItemRepositoryImpl) Create(ctx context.Context, orderItem *entities.OrderItem) error {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Creating order item %s", orderItem.ID)
 
+	unitPrice, totalPrice, err := convertItemPrices(orderItem)
+	if err != nil {
+		return xcomp.WrapOp("OrderItemRepository.Create", "order_item", orderItem.ID, err)
+	}
+
 	params := gen.CreateOrderItemParams{
 		ID:          uuidToPgUUID(orderItem.ID),
 		OrderID:     uuidToPgUUID(orderItem.OrderID),
 		ProductID:   uuidToPgUUID(orderItem.ProductID),
 		ProductName: orderItem.ProductName,
 		Quantity:    orderItem.Quantity,
-		UnitPrice:   float64ToNumeric(orderItem.UnitPrice),
-		TotalPrice:  float64ToNumeric(orderItem.TotalPrice),
+		UnitPrice:   unitPrice,
+		TotalPrice:  totalPrice,
 	}
 
-	_, err := r.Q.CreateOrderItem(ctx, params)
-	return err
+	_, err = r.q(ctx).CreateOrderItem(ctx, params)
+	return xcomp.WrapOp("OrderItemRepository.Create", "order_item", orderItem.ID, err)
 }
 
 func (r *OrderItemRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.OrderItem, error) {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Getting order item by ID %s", id)
 
-	row, err := r.Q.GetOrderItemByID(ctx, uuidToPgUUID(id))
+	row, err := r.q(ctx).GetOrderItemByID(ctx, uuidToPgUUID(id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, xcomp.WrapOp("OrderItemRepository.GetByID", "order_item", id, entities.ErrOrderItemNotFound)
+	}
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderItemRepository.GetByID", "order_item", id, err)
 	}
 
-	return convertOrderItemFromDB(*row), nil
+	item, err := convertOrderItemFromDB(*row)
+	if err != nil {
+		return nil, xcomp.WrapOp("OrderItemRepository.GetByID", "order_item", id, err)
+	}
+	return item, nil
 }
 
 func (r *OrderItemRepositoryImpl) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.OrderItem, error) {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Getting order items for order %s", orderID)
 
-	rows, err := r.Q.GetOrderItemsByOrderID(ctx, uuidToPgUUID(orderID))
+	rows, err := r.q(ctx).GetOrderItemsByOrderID(ctx, uuidToPgUUID(orderID))
 	if err != nil {
-		return nil, err
+		return nil, xcomp.WrapOp("OrderItemRepository.GetByOrderID", "order", orderID, err)
 	}
 
 	orderItems := make([]*entities.OrderItem, len(rows))
 	for i, row := range rows {
-		orderItems[i] = convertOrderItemFromDB(*row)
+		item, err := convertOrderItemFromDB(*row)
+		if err != nil {
+			return nil, xcomp.WrapOp("OrderItemRepository.GetByOrderID", "order_item", pgUUIDToUUID(row.ID), err)
+		}
+		orderItems[i] = item
 	}
 
 	return orderItems, nil
 }
 
 func (r *OrderItemRepositoryImpl) Update(ctx context.Context, orderItem *entities.OrderItem) error {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Updating order item %s", orderItem.ID)
 
+	unitPrice, totalPrice, err := convertItemPrices(orderItem)
+	if err != nil {
+		return xcomp.WrapOp("OrderItemRepository.Update", "order_item", orderItem.ID, err)
+	}
+
 	params := gen.UpdateOrderItemParams{
 		ID:         uuidToPgUUID(orderItem.ID),
 		Quantity:   orderItem.Quantity,
-		UnitPrice:  float64ToNumeric(orderItem.UnitPrice),
-		TotalPrice: float64ToNumeric(orderItem.TotalPrice),
+		UnitPrice:  unitPrice,
+		TotalPrice: totalPrice,
 	}
 
-	_, err := r.Q.UpdateOrderItem(ctx, params)
-	return err
+	_, err = r.q(ctx).UpdateOrderItem(ctx, params)
+	return xcomp.WrapOp("OrderItemRepository.Update", "order_item", orderItem.ID, err)
 }
 
 func (r *OrderItemRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Deleting order item %s", id)
 
-	return r.Q.DeleteOrderItem(ctx, uuidToPgUUID(id))
+	return xcomp.WrapOp("OrderItemRepository.Delete", "order_item", id, r.q(ctx).DeleteOrderItem(ctx, uuidToPgUUID(id)))
 }
 
 func (r *OrderItemRepositoryImpl) DeleteByOrderID(ctx context.Context, orderID uuid.UUID) error {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Deleting order items for order %s", orderID)
 
-	return r.Q.DeleteOrderItemsByOrderID(ctx, uuidToPgUUID(orderID))
+	return xcomp.WrapOp("OrderItemRepository.DeleteByOrderID", "order", orderID, r.q(ctx).DeleteOrderItemsByOrderID(ctx, uuidToPgUUID(orderID)))
 }
 
 func (r *OrderItemRepositoryImpl) CreateBatch(ctx context.Context, orderItems []*entities.OrderItem) error {
-	r.ensureQueries()
 	log.Printf("OrderItemRepository: Creating batch of order items")
 
 	for _, item := range orderItems {
@@ -277,15 +354,24 @@ func (r *OrderItemRepositoryImpl) CreateBatch(ctx context.Context, orderItems []
 	return nil
 }
 
-func convertOrderFromDB(row gen.Order) *entities.Order {
+func convertOrderFromDB(row gen.Order) (*entities.Order, error) {
+	var amounts [4]float64
+	for i, n := range []pgtype.Numeric{row.TotalAmount, row.ShippingCost, row.TaxAmount, row.DiscountAmount} {
+		amount, err := numericToFloat64(n)
+		if err != nil {
+			return nil, err
+		}
+		amounts[i] = amount
+	}
+
 	order := &entities.Order{
 		ID:              pgUUIDToUUID(row.ID),
 		CustomerID:      pgUUIDToUUID(row.CustomerID),
 		Status:          entities.OrderStatus(row.Status),
-		TotalAmount:     numericToFloat64(row.TotalAmount),
-		ShippingCost:    numericToFloat64(row.ShippingCost),
-		TaxAmount:       numericToFloat64(row.TaxAmount),
-		DiscountAmount:  numericToFloat64(row.DiscountAmount),
+		TotalAmount:     amounts[0],
+		ShippingCost:    amounts[1],
+		TaxAmount:       amounts[2],
+		DiscountAmount:  amounts[3],
 		Notes:           row.Notes,
 		ShippingAddress: row.ShippingAddress,
 		BillingAddress:  row.BillingAddress,
@@ -299,19 +385,28 @@ func convertOrderFromDB(row gen.Order) *entities.Order {
 		order.UpdatedAt = row.UpdatedAt.Time
 	}
 
-	return order
+	return order, nil
 }
 
-func convertOrderItemFromDB(row gen.OrderItem) *entities.OrderItem {
+func convertOrderItemFromDB(row gen.OrderItem) (*entities.OrderItem, error) {
+	unitPrice, err := numericToFloat64(row.UnitPrice)
+	if err != nil {
+		return nil, err
+	}
+	totalPrice, err := numericToFloat64(row.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entities.OrderItem{
 		ID:          pgUUIDToUUID(row.ID),
 		OrderID:     pgUUIDToUUID(row.OrderID),
 		ProductID:   pgUUIDToUUID(row.ProductID),
 		ProductName: row.ProductName,
 		Quantity:    row.Quantity,
-		UnitPrice:   numericToFloat64(row.UnitPrice),
-		TotalPrice:  numericToFloat64(row.TotalPrice),
-	}
+		UnitPrice:   unitPrice,
+		TotalPrice:  totalPrice,
+	}, nil
 }
 
 func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
@@ -328,17 +423,82 @@ func pgUUIDToUUID(u pgtype.UUID) uuid.UUID {
 	return u.Bytes
 }
 
-func float64ToNumeric(f float64) pgtype.Numeric {
-	cents := int64(f * 100)
-	return pgtype.Numeric{
-		Int:   big.NewInt(cents),
-		Valid: true,
+// float64ToNumeric stores the shortest decimal that round-trips f, so 19.99
+// is written as 1999e-2 rather than a truncated binary approximation
+func float64ToNumeric(f float64) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	if err := n.Scan(strconv.FormatFloat(f, 'f', -1, 64)); err != nil {
+		return pgtype.Numeric{}, fmt.Errorf("failed to convert %v to numeric: %w", f, err)
 	}
+	return n, nil
 }
 
-func numericToFloat64(n pgtype.Numeric) float64 {
+type orderAmounts struct {
+	total, shipping, tax, discount pgtype.Numeric
+}
+
+func convertOrderAmounts(order *entities.Order) (amounts orderAmounts, err error) {
+	if amounts.total, err = float64ToNumeric(order.TotalAmount); err != nil {
+		return orderAmounts{}, err
+	}
+	if amounts.shipping, err = float64ToNumeric(order.ShippingCost); err != nil {
+		return orderAmounts{}, err
+	}
+	if amounts.tax, err = float64ToNumeric(order.TaxAmount); err != nil {
+		return orderAmounts{}, err
+	}
+	if amounts.discount, err = float64ToNumeric(order.DiscountAmount); err != nil {
+		return orderAmounts{}, err
+	}
+	return amounts, nil
+}
+
+func convertItemPrices(item *entities.OrderItem) (unitPrice, totalPrice pgtype.Numeric, err error) {
+	if unitPrice, err = float64ToNumeric(item.UnitPrice); err != nil {
+		return pgtype.Numeric{}, pgtype.Numeric{}, err
+	}
+	if totalPrice, err = float64ToNumeric(item.TotalPrice); err != nil {
+		return pgtype.Numeric{}, pgtype.Numeric{}, err
+	}
+	return unitPrice, totalPrice, nil
+}
+
+// numericToFloat64 honours the value's exponent, so numerics written with
+// any scale read back correctly. NULL reads as 0; NaN and infinities, which no
+// amount can hold, are an error rather than a silent 0.
+func numericToFloat64(n pgtype.Numeric) (float64, error) {
 	if !n.Valid {
-		return 0.0
+		return 0, nil
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite || n.Int == nil {
+		return 0, fmt.Errorf("numeric %s is not a finite amount", numericString(n))
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(n.Exp))), nil)
+	r := new(big.Rat).SetInt(n.Int)
+	if n.Exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(scale))
+	} else {
+		r.Mul(r, new(big.Rat).SetInt(scale))
+	}
+	f, _ := r.Float64()
+	return f, nil
+}
+
+func numericString(n pgtype.Numeric) string {
+	switch {
+	case n.NaN:
+		return "NaN"
+	case n.InfinityModifier == pgtype.Infinity:
+		return "Infinity"
+	case n.InfinityModifier == pgtype.NegativeInfinity:
+		return "-Infinity"
+	}
+	return "without digits"
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
 	}
-	return float64(n.Int.Int64()) / 100.0
+	return v
 }
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"example/modules/order/domain/entities"
+	"example/modules/order/infrastructure/query/gen"
+
+	"xcomp"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run with -race: concurrent first calls must build the queries exactly once
+func TestOrderRepositoryConcurrentGetByID(t *testing.T) {
+	// Nothing listens on port 1, so every query fails fast after the lazy init
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/none?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	repo := &OrderRepositoryImpl{DB: pool}
+
+	const callers = 16
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetByID(context.Background(), uuid.New()); err == nil {
+				t.Error("GetByID against an unreachable database succeeded")
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx := context.Background()
+	if repo.q(ctx) != repo.q(ctx) {
+		t.Fatal("queries were built more than once")
+	}
+}
+
+func TestFloat64ToNumericRoundTrips(t *testing.T) {
+	for _, f := range []float64{0, 19.99, 0.1, 1234567.89, -5.5} {
+		n, err := float64ToNumeric(f)
+		if err != nil {
+			t.Fatalf("float64ToNumeric(%v): %v", f, err)
+		}
+		if got, err := numericToFloat64(n); err != nil || got != f {
+			t.Fatalf("round trip of %v = %v, %v", f, got, err)
+		}
+	}
+}
+
+func TestNumericToFloat64HonoursExponent(t *testing.T) {
+	tests := []struct {
+		n    pgtype.Numeric
+		want float64
+	}{
+		{pgtype.Numeric{Int: big.NewInt(1999), Exp: -2, Valid: true}, 19.99},
+		{pgtype.Numeric{Int: big.NewInt(19999), Exp: -3, Valid: true}, 19.999},
+		{pgtype.Numeric{Int: big.NewInt(5), Exp: 2, Valid: true}, 500},
+		{pgtype.Numeric{}, 0},
+	}
+	for _, tt := range tests {
+		got, err := numericToFloat64(tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("numericToFloat64(%+v) = %v, %v; want %v", tt.n, got, err, tt.want)
+		}
+	}
+}
+
+func TestNumericToFloat64RejectsNonFiniteValues(t *testing.T) {
+	for _, n := range []pgtype.Numeric{
+		{NaN: true, Valid: true},
+		{InfinityModifier: pgtype.Infinity, Valid: true},
+		{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+		{Valid: true},
+	} {
+		if got, err := numericToFloat64(n); err == nil {
+			t.Errorf("numericToFloat64(%+v) = %v, want an error", n, got)
+		}
+	}
+}
+
+func TestConvertOrderFromDBReportsNaNAmount(t *testing.T) {
+	total, _ := float64ToNumeric(10)
+	row := gen.Order{TotalAmount: total, TaxAmount: pgtype.Numeric{NaN: true, Valid: true}}
+	if order, err := convertOrderFromDB(row); err == nil {
+		t.Fatalf("convertOrderFromDB = %+v, want an error for a NaN tax amount", order)
+	}
+}
+
+func TestConvertOrderAmountsReportsUnconvertibleValue(t *testing.T) {
+	order := &entities.Order{TotalAmount: 10, TaxAmount: math.Inf(1)}
+	if _, err := convertOrderAmounts(order); err == nil {
+		t.Fatal("convertOrderAmounts accepted an infinite tax amount")
+	}
+
+	item := &entities.OrderItem{UnitPrice: 2, TotalPrice: math.Inf(-1)}
+	if _, _, err := convertItemPrices(item); err == nil {
+		t.Fatal("convertItemPrices accepted an infinite total price")
+	}
+}
+
+func TestOrderRepositoriesWrapErrorsWithOperation(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/none?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	orders := &OrderRepositoryImpl{DB: pool}
+	items := &OrderItemRepositoryImpl{DB: pool}
+	id := uuid.New()
+	item := &entities.OrderItem{ID: id, OrderID: id, ProductID: id, Quantity: 1, UnitPrice: 1, TotalPrice: 1}
+
+	calls := map[string]func() error{
+		"OrderRepository.GetByCustomerID": func() error { _, err := orders.GetByCustomerID(ctx, id, 10, 0); return err },
+		"OrderRepository.GetByStatus": func() error {
+			_, err := orders.GetByStatus(ctx, entities.OrderStatusPending, 10, 0)
+			return err
+		},
+		"OrderRepository.GetAll":               func() error { _, err := orders.GetAll(ctx, 10, 0); return err },
+		"OrderRepository.Count":                func() error { _, err := orders.Count(ctx); return err },
+		"OrderRepository.CountByCustomerID":    func() error { _, err := orders.CountByCustomerID(ctx, id); return err },
+		"OrderItemRepository.Create":           func() error { return items.Create(ctx, item) },
+		"OrderItemRepository.GetByID":          func() error { _, err := items.GetByID(ctx, id); return err },
+		"OrderItemRepository.GetByOrderID":     func() error { _, err := items.GetByOrderID(ctx, id); return err },
+		"OrderItemRepository.Update":           func() error { return items.Update(ctx, item) },
+		"OrderItemRepository.Delete":           func() error { return items.Delete(ctx, id) },
+		"OrderItemRepository.DeleteByOrderID":  func() error { return items.DeleteByOrderID(ctx, id) },
+		"OrderItemRepository.Create via batch": func() error { return items.CreateBatch(ctx, []*entities.OrderItem{item}) },
+	}
+	for name, call := range calls {
+		err := call()
+		var opErr *xcomp.OpError
+		if !errors.As(err, &opErr) {
+			t.Errorf("%s returned %v, want an *xcomp.OpError", name, err)
+			continue
+		}
+		if want, _, _ := strings.Cut(name, " via"); opErr.Op != want {
+			t.Errorf("%s wrapped its error as %q", name, opErr.Op)
+		}
+	}
+}
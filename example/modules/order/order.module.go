@@ -2,66 +2,54 @@ package order
 
 import (
 	"example/modules/order/application/services"
-	"example/modules/order/domain/interfaces"
+	"example/modules/order/domain/entities"
 	"example/modules/order/infrastructure/repositories"
 	"xcomp"
 )
 
 func NewOrderModule() xcomp.Module {
 	return xcomp.NewModule().
-		AddFactory("OrderService", func(c *xcomp.Container) any {
-			service := services.NewOrderService()
-
-			// Auto inject Logger (uppercase field with inject tag)
-			if err := c.Inject(service); err != nil {
-				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject OrderService Logger",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject OrderService Logger: " + err.Error())
-			}
-
-			// Manual inject lowercase fields via method
-			orderRepo := c.Get("OrderRepository").(interfaces.OrderRepository)
-			orderItemRepo := c.Get("OrderItemRepository").(interfaces.OrderItemRepository)
-			orderCacheRepo := c.Get("OrderCacheRepository").(interfaces.OrderCacheRepository)
-
-			service.SetDependencies(orderRepo, orderItemRepo, orderCacheRepo)
-
-			return service
+		Named("order").
+		AddRoutes("/orders", "OrderController").
+		AddConstructor("OrderService", newOrderService).
+		AddFactory("OrderTransitionPolicy", func(c *xcomp.Container) any {
+			return &services.DefaultOrderTransitionPolicy{}
 		}).
-		AddFactory("OrderRepository", func(c *xcomp.Container) any {
+		AddConstructor("OrderRepository", func(c *xcomp.Container) *repositories.OrderRepositoryImpl {
 			repo := &repositories.OrderRepositoryImpl{}
-			if err := c.Inject(repo); err != nil {
-				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject OrderRepository dependencies",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject OrderRepository dependencies: " + err.Error())
-			}
+			c.MustInject(repo)
 			return repo
 		}).
-		AddFactory("OrderItemRepository", func(c *xcomp.Container) any {
+		AddConstructor("OrderItemRepository", func(c *xcomp.Container) *repositories.OrderItemRepositoryImpl {
 			repo := &repositories.OrderItemRepositoryImpl{}
-			if err := c.Inject(repo); err != nil {
-				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject OrderItemRepository dependencies",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject OrderItemRepository dependencies: " + err.Error())
-			}
+			c.MustInject(repo)
 			return repo
 		}).
-		AddFactory("OrderCacheRepository", func(c *xcomp.Container) any {
+		AddConstructor("OrderCacheRepository", func(c *xcomp.Container) *repositories.OrderCacheRepositoryImpl {
 			cacheRepo := &repositories.OrderCacheRepositoryImpl{}
-			if err := c.Inject(cacheRepo); err != nil {
-				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject OrderCacheRepository dependencies",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject OrderCacheRepository dependencies: " + err.Error())
-			}
+			c.MustInject(cacheRepo)
 			return cacheRepo
 		}).
 		Build()
 }
+
+// newOrderService builds the service; the repositories arrive through its setters
+func newOrderService(c *xcomp.Container) *services.OrderService {
+	service := services.NewOrderService()
+	c.MustInject(service)
+
+	// Defaults apply for keys missing from config
+	limits := orderLimitsConfig{MaxItems: 100, MaxTotal: 1000000}
+	c.MustInject(&limits)
+	service.SetLimits(entities.OrderLimits{
+		MaxItems: limits.MaxItems,
+		MaxTotal: limits.MaxTotal,
+	})
+
+	return service
+}
+
+type orderLimitsConfig struct {
+	MaxItems int     `config:"order.max_items"`
+	MaxTotal float64 `config:"order.max_total"`
+}
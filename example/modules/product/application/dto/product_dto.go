@@ -3,23 +3,46 @@ package dto
 import (
 	"time"
 
+	"example/modules/product/domain/entities"
+
+	"xcomp"
+
 	"github.com/google/uuid"
 )
 
 type CreateProductRequest struct {
-	Name          string  `json:"name" validate:"required,min=1,max=255"`
-	Description   *string `json:"description" validate:"omitempty,max=1000"`
-	Price         float64 `json:"price" validate:"required,gte=0"`
-	StockQuantity int32   `json:"stock_quantity" validate:"gte=0"`
-	Category      *string `json:"category" validate:"omitempty,max=100"`
+	Name          string      `json:"name" validate:"required,min=1,max=255"`
+	Description   *string     `json:"description" validate:"omitempty,max=1000"`
+	Price         xcomp.Money `json:"price" validate:"gte=0"`
+	StockQuantity int32       `json:"stock_quantity" validate:"gte=0"`
+	Category      *string     `json:"category" validate:"omitempty,max=100"`
 }
 
 type UpdateProductRequest struct {
-	Name          string  `json:"name" validate:"required,min=1,max=255"`
-	Description   *string `json:"description" validate:"omitempty,max=1000"`
-	Price         float64 `json:"price" validate:"required,gte=0"`
-	StockQuantity int32   `json:"stock_quantity" validate:"gte=0"`
-	Category      *string `json:"category" validate:"omitempty,max=100"`
+	Name          string      `json:"name" validate:"required,min=1,max=255"`
+	Description   *string     `json:"description" validate:"omitempty,max=1000"`
+	Price         xcomp.Money `json:"price" validate:"gte=0"`
+	StockQuantity int32       `json:"stock_quantity" validate:"gte=0"`
+	Category      *string     `json:"category" validate:"omitempty,max=100"`
+}
+
+func (r *CreateProductRequest) ToEntity() *entities.Product {
+	return &entities.Product{
+		Name:          r.Name,
+		Description:   r.Description,
+		Price:         r.Price.Float64(),
+		StockQuantity: r.StockQuantity,
+		Category:      r.Category,
+		IsActive:      true,
+	}
+}
+
+func (r *UpdateProductRequest) ApplyTo(product *entities.Product) {
+	product.Name = r.Name
+	product.Description = r.Description
+	product.Price = r.Price.Float64()
+	product.StockQuantity = r.StockQuantity
+	product.Category = r.Category
 }
 
 type UpdateStockRequest struct {
@@ -27,24 +50,20 @@ type UpdateStockRequest struct {
 }
 
 type ProductResponse struct {
-	ID            uuid.UUID `json:"id"`
-	Name          string    `json:"name"`
-	Description   *string   `json:"description"`
-	Price         float64   `json:"price"`
-	StockQuantity int32     `json:"stock_quantity"`
-	Category      *string   `json:"category"`
-	IsActive      bool      `json:"is_active"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID   `json:"id"`
+	Name          string      `json:"name"`
+	Description   *string     `json:"description"`
+	Price         xcomp.Money `json:"price"`
+	StockQuantity int32       `json:"stock_quantity"`
+	Category      *string     `json:"category"`
+	IsActive      bool        `json:"is_active"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	// Relevance is only set on search results
+	Relevance *float64 `json:"relevance,omitempty"`
 }
 
-type ProductListResponse struct {
-	Products   []*ProductResponse `json:"products"`
-	TotalCount int64              `json:"total_count"`
-	Page       int32              `json:"page"`
-	PageSize   int32              `json:"page_size"`
-	TotalPages int32              `json:"total_pages"`
-}
+type ProductListResponse = xcomp.Page[*ProductResponse]
 
 type ProductSearchRequest struct {
 	Query    string `json:"query" validate:"required,min=1"`
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"testing"
+
+	"xcomp"
+)
+
+func TestCreateProductRequestAllowsFreeProducts(t *testing.T) {
+	request := &CreateProductRequest{Name: "Sticker", Price: 0}
+	if err := xcomp.Validate(request); err != nil {
+		t.Fatalf("Validate = %v, want a zero price to be valid", err)
+	}
+
+	request.Price = -1
+	fields := xcomp.FieldErrors(xcomp.Validate(request))
+	if len(fields) != 1 || fields[0].Field != "price" || fields[0].Rule != "gte" {
+		t.Fatalf("FieldErrors = %+v, want price failing gte", fields)
+	}
+}
@@ -2,8 +2,9 @@ package services
 
 import (
 	"context"
-	"math"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"example/modules/product/application/dto"
 	"example/modules/product/domain/entities"
@@ -15,21 +16,34 @@ import (
 )
 
 type ProductService struct {
-	productRepo      interfaces.ProductRepository      // lowercase - manual injection
-	productCacheRepo interfaces.ProductCacheRepository // lowercase - manual injection
+	productRepo      interfaces.ProductRepository      // lowercase - setter injection
+	productCacheRepo interfaces.ProductCacheRepository // lowercase - setter injection
 	Logger           xcomp.Logger                      `inject:"Logger"` // uppercase - auto injection
+
+	SearchMinQueryLength int `config:"search.min_query_length"`
+	SearchMaxQueryLength int `config:"search.max_query_length"`
 }
 
 func NewProductService() *ProductService {
-	return &ProductService{}
+	return &ProductService{
+		SearchMinQueryLength: 2,
+		SearchMaxQueryLength: 100,
+	}
 }
 
-// Method injection for lowercase fields
-func (ps *ProductService) SetDependencies(
-	productRepo interfaces.ProductRepository,
-	productCacheRepo interfaces.ProductCacheRepository,
-) {
+// InjectSetters lets the container set the lowercase fields through their setters
+func (ps *ProductService) InjectSetters() map[string]string {
+	return map[string]string{
+		"SetProductRepository":      "ProductRepository",
+		"SetProductCacheRepository": "ProductCacheRepository",
+	}
+}
+
+func (ps *ProductService) SetProductRepository(productRepo interfaces.ProductRepository) {
 	ps.productRepo = productRepo
+}
+
+func (ps *ProductService) SetProductCacheRepository(productCacheRepo interfaces.ProductCacheRepository) {
 	ps.productCacheRepo = productCacheRepo
 }
 
@@ -40,44 +54,21 @@ func (ps *ProductService) GetServiceName() string {
 func (ps *ProductService) GetProduct(ctx context.Context, id uuid.UUID) (*dto.ProductResponse, error) {
 	ps.Logger.Debug("Getting product", xcomp.Field("product_id", id))
 
-	product, err := ps.productCacheRepo.Get(ctx, id)
-	if err != nil {
-		ps.Logger.Debug("Product not found in cache, fetching from database",
-			xcomp.Field("product_id", id),
-			xcomp.Field("cache_error", err))
-
-		product, err = ps.productRepo.GetByID(ctx, id)
-		if err != nil {
-			ps.Logger.Error("Failed to get product from database",
-				xcomp.Field("product_id", id),
-				xcomp.Field("error", err))
-			return nil, err
-		}
-
-		if setErr := ps.productCacheRepo.Set(ctx, product, 5*time.Minute); setErr != nil {
-			ps.Logger.Warn("Failed to cache product",
-				xcomp.Field("product_id", id),
-				xcomp.Field("error", setErr))
-		}
-	} else if product == nil {
+	product, err := xcomp.CacheAside(ctx, ps.productCacheRepo, id, 5*time.Minute, func(ctx context.Context) (*entities.Product, error) {
 		ps.Logger.Debug("Product cache miss, fetching from database",
 			xcomp.Field("product_id", id))
 
-		product, err = ps.productRepo.GetByID(ctx, id)
+		product, err := ps.productRepo.GetByID(ctx, id)
 		if err != nil {
 			ps.Logger.Error("Failed to get product from database",
 				xcomp.Field("product_id", id),
-				xcomp.Field("error", err))
+				xcomp.Err(err))
 			return nil, err
 		}
-
-		if setErr := ps.productCacheRepo.Set(ctx, product, 5*time.Minute); setErr != nil {
-			ps.Logger.Warn("Failed to cache product",
-				xcomp.Field("product_id", id),
-				xcomp.Field("error", setErr))
-		}
-	} else {
-		ps.Logger.Debug("Product found in cache", xcomp.Field("product_id", id))
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	ps.Logger.Info("Product retrieved successfully",
@@ -89,14 +80,7 @@ func (ps *ProductService) GetProduct(ctx context.Context, id uuid.UUID) (*dto.Pr
 
 func (ps *ProductService) ListProducts(ctx context.Context, page, pageSize int32) (*dto.ProductListResponse, error) {
 	ps.Logger.Debug("Getting product", xcomp.Field("page", page), xcomp.Field("page_size", pageSize))
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 
 	products, err := ps.productRepo.List(ctx, pageSize, offset)
 	if err != nil {
@@ -108,32 +92,12 @@ func (ps *ProductService) ListProducts(ctx context.Context, page, pageSize int32
 		return nil, err
 	}
 
-	totalPages := int32(math.Ceil(float64(totalCount) / float64(pageSize)))
-
-	response := &dto.ProductListResponse{
-		Products:   make([]*dto.ProductResponse, len(products)),
-		TotalCount: totalCount,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}
-
-	for i, product := range products {
-		response.Products[i] = ps.toProductResponse(product)
-	}
-
-	return response, nil
+	response := xcomp.MapPage(products, totalCount, page, pageSize, ps.toProductResponse)
+	return &response, nil
 }
 
 func (ps *ProductService) ListProductsByCategory(ctx context.Context, category string, page, pageSize int32) (*dto.ProductListResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
+	page, pageSize, offset := xcomp.PageOffset(page, pageSize)
 
 	products, err := ps.productRepo.ListByCategory(ctx, category, pageSize, offset)
 	if err != nil {
@@ -145,54 +109,34 @@ func (ps *ProductService) ListProductsByCategory(ctx context.Context, category s
 		return nil, err
 	}
 
-	totalPages := int32(math.Ceil(float64(totalCount) / float64(pageSize)))
-
-	response := &dto.ProductListResponse{
-		Products:   make([]*dto.ProductResponse, len(products)),
-		TotalCount: totalCount,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}
-
-	for i, product := range products {
-		response.Products[i] = ps.toProductResponse(product)
-	}
-
-	return response, nil
+	response := xcomp.MapPage(products, totalCount, page, pageSize, ps.toProductResponse)
+	return &response, nil
 }
 
 func (ps *ProductService) SearchProducts(ctx context.Context, searchReq *dto.ProductSearchRequest) (*dto.ProductListResponse, error) {
-	if searchReq.Page < 1 {
-		searchReq.Page = 1
-	}
-	if searchReq.PageSize < 1 || searchReq.PageSize > 100 {
-		searchReq.PageSize = 10
-	}
-
-	offset := (searchReq.Page - 1) * searchReq.PageSize
+	page, pageSize, offset := xcomp.PageOffset(searchReq.Page, searchReq.PageSize)
 
-	products, err := ps.productRepo.Search(ctx, searchReq.Query, searchReq.PageSize, offset)
+	query, err := normalizeSearchQuery(searchReq.Query, ps.SearchMinQueryLength, ps.SearchMaxQueryLength)
 	if err != nil {
 		return nil, err
 	}
 
-	totalCount := int64(len(products))
-	totalPages := int32(math.Ceil(float64(totalCount) / float64(searchReq.PageSize)))
-
-	response := &dto.ProductListResponse{
-		Products:   make([]*dto.ProductResponse, len(products)),
-		TotalCount: totalCount,
-		Page:       searchReq.Page,
-		PageSize:   searchReq.PageSize,
-		TotalPages: totalPages,
+	var category *string
+	if searchReq.Category != "" {
+		category = &searchReq.Category
 	}
 
-	for i, product := range products {
-		response.Products[i] = ps.toProductResponse(product)
+	products, err := ps.productRepo.Search(ctx, query, category, pageSize, offset)
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	response := xcomp.MapPage(products, int64(len(products)), page, pageSize, func(match *entities.ProductSearchResult) *dto.ProductResponse {
+		product := ps.toProductResponse(match.Product)
+		product.Relevance = &match.Relevance
+		return product
+	})
+	return &response, nil
 }
 
 func (ps *ProductService) CreateProduct(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
@@ -201,19 +145,11 @@ func (ps *ProductService) CreateProduct(ctx context.Context, req *dto.CreateProd
 		xcomp.Field("price", req.Price),
 		xcomp.Field("stock_quantity", req.StockQuantity))
 
-	product := &entities.Product{
-		Name:          req.Name,
-		Description:   req.Description,
-		Price:         req.Price,
-		StockQuantity: req.StockQuantity,
-		Category:      req.Category,
-		IsActive:      true,
-	}
-
-	if err := product.Validate(); err != nil {
+	product, err := xcomp.ToEntity(req)
+	if err != nil {
 		ps.Logger.Error("Product validation failed",
 			xcomp.Field("product_name", req.Name),
-			xcomp.Field("error", err))
+			xcomp.Err(err))
 		return nil, err
 	}
 
@@ -221,7 +157,7 @@ func (ps *ProductService) CreateProduct(ctx context.Context, req *dto.CreateProd
 	if err != nil {
 		ps.Logger.Error("Failed to create product",
 			xcomp.Field("product_name", req.Name),
-			xcomp.Field("error", err))
+			xcomp.Err(err))
 		return nil, err
 	}
 
@@ -238,13 +174,7 @@ func (ps *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *
 		return nil, err
 	}
 
-	existingProduct.Name = req.Name
-	existingProduct.Description = req.Description
-	existingProduct.Price = req.Price
-	existingProduct.StockQuantity = req.StockQuantity
-	existingProduct.Category = req.Category
-
-	if err := existingProduct.Validate(); err != nil {
+	if err := xcomp.ApplyTo(req, existingProduct); err != nil {
 		return nil, err
 	}
 
@@ -284,7 +214,7 @@ func (ps *ProductService) toProductResponse(product *entities.Product) *dto.Prod
 		ID:            product.ID,
 		Name:          product.Name,
 		Description:   product.Description,
-		Price:         product.Price,
+		Price:         xcomp.Money(product.Price),
 		StockQuantity: product.StockQuantity,
 		Category:      product.Category,
 		IsActive:      product.IsActive,
@@ -292,3 +222,17 @@ func (ps *ProductService) toProductResponse(product *entities.Product) *dto.Prod
 		UpdatedAt:     product.UpdatedAt,
 	}
 }
+
+// normalizeSearchQuery trims and collapses whitespace, then enforces the length
+// bounds; one-character queries match nearly every row and scan the whole table
+func normalizeSearchQuery(query string, minLength, maxLength int) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	length := utf8.RuneCountInString(query)
+	if length < minLength {
+		return "", entities.ErrSearchQueryTooShort.Withf("use at least %d characters", minLength)
+	}
+	if maxLength > 0 && length > maxLength {
+		return "", entities.ErrSearchQueryTooLong.Withf("use at most %d characters", maxLength)
+	}
+	return query, nil
+}
@@ -1,11 +1,14 @@
 package entities
 
-import "errors"
+import "xcomp"
 
+// Each error carries the HTTP status it is reported with, see xcomp.AppError
 var (
-	ErrProductNotFound      = errors.New("product not found")
-	ErrProductNameRequired  = errors.New("product name is required")
-	ErrProductPriceInvalid  = errors.New("product price must be greater than or equal to 0")
-	ErrProductStockInvalid  = errors.New("product stock quantity must be greater than or equal to 0")
-	ErrProductAlreadyExists = errors.New("product already exists")
+	ErrProductNotFound      = xcomp.NewNotFound("product not found")
+	ErrProductNameRequired  = xcomp.NewValidation("product name is required")
+	ErrProductPriceInvalid  = xcomp.NewValidation("product price must be greater than or equal to 0")
+	ErrProductStockInvalid  = xcomp.NewValidation("product stock quantity must be greater than or equal to 0")
+	ErrProductAlreadyExists = xcomp.NewConflict("product already exists")
+	ErrSearchQueryTooShort  = xcomp.NewBadRequest("search query is too short")
+	ErrSearchQueryTooLong   = xcomp.NewBadRequest("search query is too long")
 )
@@ -18,6 +18,13 @@ type Product struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// ProductSearchResult is a product matched by a search with its full-text relevance;
+// partial-word matches found without the full-text index score 0
+type ProductSearchResult struct {
+	Product   *Product
+	Relevance float64
+}
+
 func (p *Product) Validate() error {
 	if p.Name == "" {
 		return ErrProductNameRequired
@@ -16,7 +16,9 @@ type ProductRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
 	List(ctx context.Context, limit, offset int32) ([]*entities.Product, error)
 	ListByCategory(ctx context.Context, category string, limit, offset int32) ([]*entities.Product, error)
-	Search(ctx context.Context, query string, limit, offset int32) ([]*entities.Product, error)
+	// Search matches query against name, description and category, most relevant first;
+	// a non-nil category restricts results to that category
+	Search(ctx context.Context, query string, category *string, limit, offset int32) ([]*entities.ProductSearchResult, error)
 	Count(ctx context.Context) (int64, error)
 	CountByCategory(ctx context.Context, category string) (int64, error)
 }
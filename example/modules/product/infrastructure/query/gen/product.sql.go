@@ -192,29 +192,65 @@ func (q *Queries) ListProductsByCategory(ctx context.Context, arg ListProductsBy
 }
 
 const searchProducts = `-- name: SearchProducts :many
-SELECT id, name, description, price, stock_quantity, category, is_active, created_at, updated_at
+SELECT id, name, description, price, stock_quantity, category, is_active, created_at, updated_at,
+       ts_rank(
+           setweight(to_tsvector('english', name), 'A') ||
+           setweight(to_tsvector('english', coalesce(category, '')), 'B') ||
+           setweight(to_tsvector('english', coalesce(description, '')), 'C'),
+           websearch_to_tsquery('english', $1::text)
+       )::float8 AS relevance
 FROM products
-WHERE (name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
-  AND is_active = true
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+WHERE is_active = true
+  AND ($2::text IS NULL OR category = $2::text)
+  AND (
+      (setweight(to_tsvector('english', name), 'A') ||
+       setweight(to_tsvector('english', coalesce(category, '')), 'B') ||
+       setweight(to_tsvector('english', coalesce(description, '')), 'C'))
+          @@ websearch_to_tsquery('english', $1::text)
+      OR name ILIKE '%' || $1::text || '%'
+      OR description ILIKE '%' || $1::text || '%'
+      OR category ILIKE '%' || $1::text || '%'
+  )
+ORDER BY relevance DESC, created_at DESC
+LIMIT $3 OFFSET $4;
 `
 
 type SearchProductsParams struct {
-	Column1 *string `db:"column_1"`
-	Limit   int32   `db:"limit"`
-	Offset  int32   `db:"offset"`
+	Query        string  `db:"query"`
+	Category     *string `db:"category"`
+	ResultLimit  int32   `db:"result_limit"`
+	ResultOffset int32   `db:"result_offset"`
+}
+
+type SearchProductsRow struct {
+	ID            pgtype.UUID        `db:"id"`
+	Name          string             `db:"name"`
+	Description   *string            `db:"description"`
+	Price         pgtype.Numeric     `db:"price"`
+	StockQuantity int32              `db:"stock_quantity"`
+	Category      *string            `db:"category"`
+	IsActive      bool               `db:"is_active"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `db:"updated_at"`
+	Relevance     float64            `db:"relevance"`
 }
 
-func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]*Product, error) {
-	rows, err := q.db.Query(ctx, searchProducts, arg.Column1, arg.Limit, arg.Offset)
+// Matches whole words through the full-text index and partial words through ILIKE,
+// ranking full-text matches by relevance
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]*SearchProductsRow, error) {
+	rows, err := q.db.Query(ctx, searchProducts,
+		arg.Query,
+		arg.Category,
+		arg.ResultLimit,
+		arg.ResultOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*Product
+	var items []*SearchProductsRow
 	for rows.Next() {
-		var i Product
+		var i SearchProductsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -225,6 +261,7 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Relevance,
 		); err != nil {
 			return nil, err
 		}
@@ -18,12 +18,29 @@ ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: SearchProducts :many
-SELECT id, name, description, price, stock_quantity, category, is_active, created_at, updated_at
+-- Matches whole words through the full-text index and partial words through ILIKE,
+-- ranking full-text matches by relevance
+SELECT id, name, description, price, stock_quantity, category, is_active, created_at, updated_at,
+       ts_rank(
+           setweight(to_tsvector('english', name), 'A') ||
+           setweight(to_tsvector('english', coalesce(category, '')), 'B') ||
+           setweight(to_tsvector('english', coalesce(description, '')), 'C'),
+           websearch_to_tsquery('english', @query::text)
+       )::float8 AS relevance
 FROM products
-WHERE (name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
-  AND is_active = true
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+WHERE is_active = true
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (
+      (setweight(to_tsvector('english', name), 'A') ||
+       setweight(to_tsvector('english', coalesce(category, '')), 'B') ||
+       setweight(to_tsvector('english', coalesce(description, '')), 'C'))
+          @@ websearch_to_tsquery('english', @query::text)
+      OR name ILIKE '%' || @query::text || '%'
+      OR description ILIKE '%' || @query::text || '%'
+      OR category ILIKE '%' || @query::text || '%'
+  )
+ORDER BY relevance DESC, created_at DESC
+LIMIT @result_limit OFFSET @result_offset;
 
 -- name: CreateProduct :one
 INSERT INTO products (name, description, price, stock_quantity, category)
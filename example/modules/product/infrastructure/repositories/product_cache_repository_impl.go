@@ -2,20 +2,25 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	"example/modules/product/domain/entities"
 	"example/modules/product/domain/interfaces"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// ProductCacheRepositoryImpl keeps a short-lived in-process L1 in front of Redis (L2)
 type ProductCacheRepositoryImpl struct {
-	RedisClient *redis.Client `inject:"RedisClient"`
+	RedisClient  *redis.Client                             `inject:"RedisClient"`
+	LocalCache   *xcomp.LRUCache[string, entities.Product] `inject:"ProductLocalCache"`
+	CacheMetrics *xcomp.CacheMetrics                       `inject:"CacheMetrics"`
+	Serializer   xcomp.Serializer                          `inject:"Serializer"`
+	Logger       xcomp.Logger                              `inject:"Logger"`
 }
 
 func (r *ProductCacheRepositoryImpl) GetServiceName() string {
@@ -23,37 +28,56 @@ func (r *ProductCacheRepositoryImpl) GetServiceName() string {
 }
 
 func (r *ProductCacheRepositoryImpl) Get(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	key := r.getProductKey(ctx, id)
+	if local, ok := r.LocalCache.Get(key); ok {
+		r.CacheMetrics.Hit("product")
+		return &local, nil
+	}
+
 	if r.RedisClient == nil {
-		log.Printf("Redis client is nil, skipping cache get")
+		r.CacheMetrics.Miss("product")
 		return nil, nil
 	}
 
-	key := r.getProductKey(id)
-	log.Printf("Attempting to get product from cache with key: %s", key)
+	r.Logger.Debug("Getting product from cache", xcomp.RequestIDField(ctx), xcomp.Field("key", key))
 
 	val, err := r.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			log.Printf("Product not found in cache: %s", key)
+			r.Logger.Debug("Product not found in cache", xcomp.RequestIDField(ctx), xcomp.Field("key", key))
+			r.CacheMetrics.Miss("product")
 			return nil, nil
 		}
-		log.Printf("Error getting product from cache: %v", err)
+		r.Logger.Warn("Failed to get product from cache",
+			xcomp.RequestIDField(ctx),
+			xcomp.Field("key", key),
+			xcomp.Err(err))
 		return nil, fmt.Errorf("failed to get product from cache: %w", err)
 	}
 
-	log.Printf("Found product in cache: %s", key)
+	r.Logger.Debug("Found product in cache", xcomp.RequestIDField(ctx), xcomp.Field("key", key))
 	var product entities.Product
-	if err := json.Unmarshal([]byte(val), &product); err != nil {
-		log.Printf("Error unmarshaling product from cache: %v", err)
+	if err := r.Serializer.Unmarshal([]byte(val), &product); err != nil {
+		r.Logger.Warn("Failed to unmarshal product from cache",
+			xcomp.RequestIDField(ctx),
+			xcomp.Field("key", key),
+			xcomp.Err(err))
 		return nil, fmt.Errorf("failed to unmarshal product from cache: %w", err)
 	}
 
+	r.CacheMetrics.Hit("product")
+	r.LocalCache.Set(key, product)
 	return &product, nil
 }
 
 func (r *ProductCacheRepositoryImpl) Set(ctx context.Context, product *entities.Product, ttl time.Duration) error {
-	key := r.getProductKey(product.ID)
-	productJSON, err := json.Marshal(product)
+	key := r.getProductKey(ctx, product.ID)
+	if r.RedisClient == nil {
+		r.LocalCache.Set(key, *product)
+		return nil
+	}
+
+	productJSON, err := r.Serializer.Marshal(product)
 	if err != nil {
 		return fmt.Errorf("failed to marshal product for cache: %w", err)
 	}
@@ -62,11 +86,17 @@ func (r *ProductCacheRepositoryImpl) Set(ctx context.Context, product *entities.
 		return fmt.Errorf("failed to set product in cache: %w", err)
 	}
 
+	r.LocalCache.Set(key, *product)
 	return nil
 }
 
 func (r *ProductCacheRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	key := r.getProductKey(id)
+	key := r.getProductKey(ctx, id)
+	r.LocalCache.Delete(key)
+	if r.RedisClient == nil {
+		return nil
+	}
+
 	if err := r.RedisClient.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete product from cache: %w", err)
 	}
@@ -74,14 +104,26 @@ func (r *ProductCacheRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) e
 }
 
 func (r *ProductCacheRepositoryImpl) Clear(ctx context.Context) error {
-	iter := r.RedisClient.Scan(ctx, 0, "product:*", 0).Iterator()
+	r.LocalCache.Clear()
+	if r.RedisClient == nil {
+		return nil
+	}
+
+	iter := r.RedisClient.Scan(ctx, 0, xcomp.TenantCacheKey(ctx, "product:*"), 0).Iterator()
 	var keysToDelete []string
 
 	for iter.Next(ctx) {
+		// Stop promptly on client disconnect or shutdown instead of scanning the whole keyspace
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		keysToDelete = append(keysToDelete, iter.Val())
 	}
 
 	if err := iter.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		return fmt.Errorf("failed to scan cache keys: %w", err)
 	}
 
@@ -94,8 +136,8 @@ func (r *ProductCacheRepositoryImpl) Clear(ctx context.Context) error {
 	return nil
 }
 
-func (r *ProductCacheRepositoryImpl) getProductKey(id uuid.UUID) string {
-	return fmt.Sprintf("product:%s", id.String())
+func (r *ProductCacheRepositoryImpl) getProductKey(ctx context.Context, id uuid.UUID) string {
+	return xcomp.TenantCacheKey(ctx, fmt.Sprintf("product:%s", id.String()))
 }
 
 var _ interfaces.ProductCacheRepository = (*ProductCacheRepositoryImpl)(nil)
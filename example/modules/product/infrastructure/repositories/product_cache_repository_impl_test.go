@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example/modules/product/domain/entities"
+
+	"xcomp"
+
+	"github.com/google/uuid"
+)
+
+func TestProductCacheRepositoryCountsHitsAndMisses(t *testing.T) {
+	metrics := xcomp.NewMetrics()
+	repo := &ProductCacheRepositoryImpl{
+		LocalCache:   xcomp.NewLRUCache[string, entities.Product](xcomp.LRUCacheOptions{TTL: time.Minute}),
+		CacheMetrics: xcomp.NewCacheMetrics(metrics),
+		Logger:       xcomp.NewDevelopmentLogger(),
+	}
+	ctx := context.Background()
+	product := &entities.Product{ID: uuid.New(), Name: "Keyboard"}
+
+	if cached, err := repo.Get(ctx, product.ID); err != nil || cached != nil {
+		t.Fatalf("Get before Set = %v, %v; want a miss", cached, err)
+	}
+	if err := repo.Set(ctx, product, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if cached, err := repo.Get(ctx, product.ID); err != nil || cached == nil || cached.Name != product.Name {
+		t.Fatalf("Get after Set = %v, %v; want the cached product", cached, err)
+	}
+
+	hits := metrics.Counter("cache_hits_total", "entity", "product").Value()
+	misses := metrics.Counter("cache_misses_total", "entity", "product").Value()
+	if hits != 1 || misses != 1 {
+		t.Fatalf("hits = %d, misses = %d; want 1 and 1", hits, misses)
+	}
+	if ratio := repo.CacheMetrics.HitRatio("product"); ratio != 0.5 {
+		t.Fatalf("HitRatio = %v, want 0.5", ratio)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"time"
 
+	"example/infrastructure/database"
 	"example/modules/product/domain/entities"
 	"example/modules/product/domain/interfaces"
 	"example/modules/product/infrastructure/query/gen"
 
+	"xcomp"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,43 +19,35 @@ import (
 
 type ProductRepositoryImpl struct {
 	DB      *pgxpool.Pool `inject:"DatabaseConnection"`
-	Queries *gen.Queries
+	queries xcomp.Lazy[*gen.Queries]
 }
 
 func (pr *ProductRepositoryImpl) GetServiceName() string {
 	return "ProductRepository"
 }
 
-func (pr *ProductRepositoryImpl) Initialize() {
-	if pr.DB != nil {
-		pr.Queries = gen.New(pr.DB)
-	}
+func (pr *ProductRepositoryImpl) q() *gen.Queries {
+	return pr.queries.Get(func() *gen.Queries {
+		return gen.New(pr.DB)
+	})
 }
 
 func (pr *ProductRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(id.String()); err != nil {
 		return nil, fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	result, err := pr.Queries.GetProduct(ctx, pgID)
+	result, err := pr.q().GetProduct(ctx, pgID)
 	if err != nil {
-		return nil, pr.convertError(err)
+		return nil, pr.convertError("ProductRepository.GetByID", id, err)
 	}
 
 	return pr.convertToEntity(result), nil
 }
 
 func (pr *ProductRepositoryImpl) List(ctx context.Context, limit, offset int32) ([]*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
-	results, err := pr.Queries.ListProducts(ctx, gen.ListProductsParams{
+	results, err := pr.q().ListProducts(ctx, gen.ListProductsParams{
 		Limit:  limit,
 		Offset: offset,
 	})
@@ -69,11 +64,7 @@ func (pr *ProductRepositoryImpl) List(ctx context.Context, limit, offset int32)
 }
 
 func (pr *ProductRepositoryImpl) ListByCategory(ctx context.Context, category string, limit, offset int32) ([]*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
-	results, err := pr.Queries.ListProductsByCategory(ctx, gen.ListProductsByCategoryParams{
+	results, err := pr.q().ListProductsByCategory(ctx, gen.ListProductsByCategoryParams{
 		Category: &category,
 		Limit:    limit,
 		Offset:   offset,
@@ -90,39 +81,46 @@ func (pr *ProductRepositoryImpl) ListByCategory(ctx context.Context, category st
 	return products, nil
 }
 
-func (pr *ProductRepositoryImpl) Search(ctx context.Context, searchQuery string, limit, offset int32) ([]*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
-	results, err := pr.Queries.SearchProducts(ctx, gen.SearchProductsParams{
-		Column1: &searchQuery,
-		Limit:   limit,
-		Offset:  offset,
+func (pr *ProductRepositoryImpl) Search(ctx context.Context, searchQuery string, category *string, limit, offset int32) ([]*entities.ProductSearchResult, error) {
+	// Escaped for the ILIKE fallback; full-text parsing ignores the backslashes
+	results, err := pr.q().SearchProducts(ctx, gen.SearchProductsParams{
+		Query:        database.EscapeLike(searchQuery),
+		Category:     category,
+		ResultLimit:  limit,
+		ResultOffset: offset,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search products: %w", err)
 	}
 
-	products := make([]*entities.Product, len(results))
+	matches := make([]*entities.ProductSearchResult, len(results))
 	for i, result := range results {
-		products[i] = pr.convertToEntity(result)
+		matches[i] = &entities.ProductSearchResult{
+			Product: pr.convertToEntity(&gen.Product{
+				ID:            result.ID,
+				Name:          result.Name,
+				Description:   result.Description,
+				Price:         result.Price,
+				StockQuantity: result.StockQuantity,
+				Category:      result.Category,
+				IsActive:      result.IsActive,
+				CreatedAt:     result.CreatedAt,
+				UpdatedAt:     result.UpdatedAt,
+			}),
+			Relevance: result.Relevance,
+		}
 	}
 
-	return products, nil
+	return matches, nil
 }
 
 func (pr *ProductRepositoryImpl) Create(ctx context.Context, product *entities.Product) (*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
 	pgPrice := pgtype.Numeric{}
 	if err := pgPrice.Scan(fmt.Sprintf("%.2f", product.Price)); err != nil {
 		return nil, fmt.Errorf("failed to convert price: %w", err)
 	}
 
-	result, err := pr.Queries.CreateProduct(ctx, gen.CreateProductParams{
+	result, err := pr.q().CreateProduct(ctx, gen.CreateProductParams{
 		Name:          product.Name,
 		Description:   product.Description,
 		Price:         pgPrice,
@@ -137,10 +135,6 @@ func (pr *ProductRepositoryImpl) Create(ctx context.Context, product *entities.P
 }
 
 func (pr *ProductRepositoryImpl) Update(ctx context.Context, product *entities.Product) (*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(product.ID.String()); err != nil {
 		return nil, fmt.Errorf("failed to convert UUID: %w", err)
@@ -151,7 +145,7 @@ func (pr *ProductRepositoryImpl) Update(ctx context.Context, product *entities.P
 		return nil, fmt.Errorf("failed to convert price: %w", err)
 	}
 
-	result, err := pr.Queries.UpdateProduct(ctx, gen.UpdateProductParams{
+	result, err := pr.q().UpdateProduct(ctx, gen.UpdateProductParams{
 		ID:            pgID,
 		Name:          product.Name,
 		Description:   product.Description,
@@ -160,60 +154,44 @@ func (pr *ProductRepositoryImpl) Update(ctx context.Context, product *entities.P
 		Category:      product.Category,
 	})
 	if err != nil {
-		return nil, pr.convertError(err)
+		return nil, pr.convertError("ProductRepository.Update", product.ID, err)
 	}
 
 	return pr.convertToEntity(result), nil
 }
 
 func (pr *ProductRepositoryImpl) UpdateStock(ctx context.Context, id uuid.UUID, stockQuantity int32) (*entities.Product, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(id.String()); err != nil {
 		return nil, fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	result, err := pr.Queries.UpdateProductStock(ctx, gen.UpdateProductStockParams{
+	result, err := pr.q().UpdateProductStock(ctx, gen.UpdateProductStockParams{
 		ID:            pgID,
 		StockQuantity: stockQuantity,
 	})
 	if err != nil {
-		return nil, pr.convertError(err)
+		return nil, pr.convertError("ProductRepository.UpdateStock", id, err)
 	}
 
 	return pr.convertToEntity(result), nil
 }
 
 func (pr *ProductRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
 	pgID := pgtype.UUID{}
 	if err := pgID.Scan(id.String()); err != nil {
 		return fmt.Errorf("failed to convert UUID: %w", err)
 	}
 
-	return pr.Queries.DeleteProduct(ctx, pgID)
+	return pr.q().DeleteProduct(ctx, pgID)
 }
 
 func (pr *ProductRepositoryImpl) Count(ctx context.Context) (int64, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
-	return pr.Queries.CountProducts(ctx)
+	return pr.q().CountProducts(ctx)
 }
 
 func (pr *ProductRepositoryImpl) CountByCategory(ctx context.Context, category string) (int64, error) {
-	if pr.Queries == nil {
-		pr.Initialize()
-	}
-
-	return pr.Queries.CountProductsByCategory(ctx, &category)
+	return pr.q().CountProductsByCategory(ctx, &category)
 }
 
 func (pr *ProductRepositoryImpl) convertToEntity(sqlcProduct *gen.Product) *entities.Product {
@@ -250,11 +228,11 @@ func (pr *ProductRepositoryImpl) convertToEntity(sqlcProduct *gen.Product) *enti
 	}
 }
 
-func (pr *ProductRepositoryImpl) convertError(err error) error {
+func (pr *ProductRepositoryImpl) convertError(op string, id any, err error) error {
 	if err.Error() == "no rows in result set" {
 		return entities.ErrProductNotFound
 	}
-	return fmt.Errorf("database error: %w", err)
+	return xcomp.WrapOp(op, "product", id, fmt.Errorf("database error: %w", err))
 }
 
 var _ interfaces.ProductRepository = (*ProductRepositoryImpl)(nil)
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run with -race: concurrent first calls must build the queries exactly once
+func TestProductRepositoryConcurrentGetByID(t *testing.T) {
+	// Nothing listens on port 1, so every query fails fast after the lazy init
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/none?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	defer pool.Close()
+
+	repo := &ProductRepositoryImpl{DB: pool}
+
+	const callers = 16
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetByID(context.Background(), uuid.New()); err == nil {
+				t.Error("GetByID against an unreachable database succeeded")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if repo.q() != repo.q() {
+		t.Fatal("queries were built more than once")
+	}
+}
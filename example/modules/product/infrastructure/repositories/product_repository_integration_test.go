@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example/modules/product/domain/entities"
+	"example/modules/product/domain/interfaces"
+	"example/testenv"
+
+	"xcomp"
+)
+
+func TestProductRepositoryRoundTrip(t *testing.T) {
+	env := testenv.New(t)
+	ctx := context.Background()
+
+	repo := xcomp.MustResolve[interfaces.ProductRepository](env.Container, "ProductRepository")
+
+	description := "Mechanical keyboard"
+	category := "peripherals"
+	created, err := repo.Create(ctx, &entities.Product{
+		Name:          "Keyboard",
+		Description:   &description,
+		Price:         89.5,
+		StockQuantity: 10,
+		Category:      &category,
+		IsActive:      true,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Name != "Keyboard" || got.Price != 89.5 || got.StockQuantity != 10 {
+		t.Fatalf("GetByID = %+v, want the created product", got)
+	}
+
+	updated, err := repo.UpdateStock(ctx, created.ID, 3)
+	if err != nil {
+		t.Fatalf("UpdateStock: %v", err)
+	}
+	if updated.StockQuantity != 3 {
+		t.Fatalf("stock after UpdateStock = %d, want 3", updated.StockQuantity)
+	}
+
+	if count, err := repo.CountByCategory(ctx, category); err != nil || count != 1 {
+		t.Fatalf("CountByCategory = %d, %v; want 1", count, err)
+	}
+
+	if err := repo.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, created.ID); !errors.Is(err, entities.ErrProductNotFound) {
+		t.Fatalf("GetByID after Delete returned %v, want %v", err, entities.ErrProductNotFound)
+	}
+}
+
+// Building every service catches wiring the harness is missing, such as a
+// dependency main.go provides but testenv does not
+func TestTestenvResolvesProductServices(t *testing.T) {
+	env := testenv.New(t)
+
+	for _, name := range []string{"ProductRepository", "ProductCacheRepository", "ProductService"} {
+		if env.Container.Get(name) == nil {
+			t.Errorf("service '%s' did not resolve", name)
+		}
+	}
+}
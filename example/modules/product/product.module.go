@@ -1,7 +1,10 @@
 package product
 
 import (
+	"time"
+
 	"example/modules/product/application/services"
+	"example/modules/product/domain/entities"
 	"example/modules/product/domain/interfaces"
 	"example/modules/product/infrastructure/repositories"
 	"xcomp"
@@ -9,34 +12,30 @@ import (
 
 func CreateProductModule() xcomp.Module {
 	return xcomp.NewModule().
-		AddFactory("ProductService", func(c *xcomp.Container) any {
-			service := &services.ProductService{}
-
-			// Auto inject Logger (uppercase field with inject tag)
-			if err := c.Inject(service); err != nil {
-				if logger, ok := c.Get("Logger").(xcomp.Logger); ok {
-					logger.Error("Failed to inject ProductService Logger",
-						xcomp.Field("error", err))
-				}
-				panic("Failed to inject ProductService Logger: " + err.Error())
-			}
-
-			// Manual inject lowercase fields via method
-			productRepo := c.Get("ProductRepository").(interfaces.ProductRepository)
-			productCacheRepo := c.Get("ProductCacheRepository").(interfaces.ProductCacheRepository)
-
-			service.SetDependencies(productRepo, productCacheRepo)
-
+		Named("product").
+		AddRoutes("/products", "ProductController").
+		// Registered by type so consumers can resolve it with xcomp.ResolveByType
+		AddProvider(xcomp.NewTypedProvider("ProductService", func(c *xcomp.Container) interfaces.ProductService {
+			service := services.NewProductService()
+			c.MustInject(service)
 			return service
-		}).
+		})).
 		AddFactory("ProductRepository", func(c *xcomp.Container) any {
 			repo := &repositories.ProductRepositoryImpl{}
-			c.Inject(repo)
+			c.MustInject(repo)
 			return repo
 		}).
+		AddFactory("ProductLocalCache", func(c *xcomp.Container) any {
+			opts := xcomp.LRUCacheOptions{MaxEntries: 1000, TTL: 30 * time.Second}
+			if config, ok := c.Get("ConfigService").(*xcomp.ConfigService); ok {
+				opts.MaxEntries = config.GetInt("product.local_cache.max_entries", opts.MaxEntries)
+				opts.TTL = config.GetDuration("product.local_cache.ttl_seconds", 30*time.Second)
+			}
+			return xcomp.NewLRUCache[string, entities.Product](opts)
+		}).
 		AddFactory("ProductCacheRepository", func(c *xcomp.Container) any {
 			cacheRepo := &repositories.ProductCacheRepositoryImpl{}
-			c.Inject(cacheRepo)
+			c.MustInject(cacheRepo)
 			return cacheRepo
 		}).
 		Build()
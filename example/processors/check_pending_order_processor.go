@@ -2,16 +2,13 @@ package processors
 
 import (
 	"context"
-	"encoding/json"
 	"example/jobs"
 	"example/modules/customer/domain/interfaces"
 	orderInterfaces "example/modules/order/domain/interfaces"
 
-	"xcomp"
-
-	"fmt"
+	"time"
 
-	"github.com/hibiken/asynq"
+	"xcomp"
 )
 
 type CheckPendingOrderProcessor struct {
@@ -32,17 +29,17 @@ func NewCheckPendingOrderProcessor(
 	}
 }
 
-func (p *CheckPendingOrderProcessor) ProcessCheckPendingOrder(ctx context.Context, t *asynq.Task) error {
-	var job jobs.CheckPendingOrderJob
-	if err := json.Unmarshal(t.Payload(), &job); err != nil {
-		p.logger.Error("Failed to unmarshal check pending order job",
-			xcomp.Field("error", err))
-		return err
-	}
-
-	p.logger.Info("Processing check pending order job",
-		xcomp.Field("job_created_at", job.CreatedAt),
-		xcomp.Field("orderService_pointer", fmt.Sprintf("%p", p.orderService)))
+// ProcessCheckPendingOrder handles jobs.TypeCheckPendingOrder; register it
+// with jobs.RegisterJob, which decodes the payload
+func (p *CheckPendingOrderProcessor) ProcessCheckPendingOrder(ctx context.Context, job *jobs.CheckPendingOrderJob) (err error) {
+	start := time.Now()
+	fields := TaskLogFields(ctx, jobs.TypeCheckPendingOrder)
+	defer func() {
+		logTaskOutcome(p.logger, fields, start, err)
+	}()
+
+	fields = append(fields, xcomp.Field("job_created_at", job.CreatedAt))
+	p.logger.Debug("Processing check pending order job", fields...)
 
 	return nil
 }
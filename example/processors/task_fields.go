@@ -0,0 +1,45 @@
+package processors
+
+import (
+	"context"
+	"time"
+
+	"xcomp"
+
+	"github.com/hibiken/asynq"
+)
+
+// TaskLogFields extracts asynq task metadata from the handler context so every
+// processor logs the same identifying fields
+func TaskLogFields(ctx context.Context, jobType string) []xcomp.LogField {
+	fields := []xcomp.LogField{xcomp.Field("job_type", jobType)}
+
+	if taskID, ok := asynq.GetTaskID(ctx); ok {
+		fields = append(fields, xcomp.Field("task_id", taskID))
+	}
+	if queue, ok := asynq.GetQueueName(ctx); ok {
+		fields = append(fields, xcomp.Field("queue", queue))
+	}
+	if retryCount, ok := asynq.GetRetryCount(ctx); ok {
+		fields = append(fields, xcomp.Field("attempt", retryCount+1))
+	}
+	if maxRetry, ok := asynq.GetMaxRetry(ctx); ok {
+		fields = append(fields, xcomp.Field("max_retry", maxRetry))
+	}
+
+	return fields
+}
+
+// logTaskOutcome logs the result of a processed task with its duration
+func logTaskOutcome(logger xcomp.Logger, fields []xcomp.LogField, start time.Time, err error) {
+	fields = append(fields, xcomp.Field("duration", time.Since(start).String()))
+
+	if err != nil {
+		fields = append(fields, xcomp.Field("outcome", "failed"), xcomp.Err(err))
+		logger.Error("Job processing failed", fields...)
+		return
+	}
+
+	fields = append(fields, xcomp.Field("outcome", "succeeded"))
+	logger.Info("Job processed", fields...)
+}
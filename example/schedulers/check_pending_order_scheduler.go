@@ -2,7 +2,11 @@ package schedulers
 
 import (
 	"context"
+	"errors"
 	"example/jobs"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"xcomp"
@@ -10,28 +14,135 @@ import (
 	"github.com/hibiken/asynq"
 )
 
-type CheckPendingOrderScheduler struct {
-	client asynq.Client
-	logger xcomp.Logger
-	ticker *time.Ticker
-	done   chan bool
+// failureWarnThreshold is how many enqueues in a row must fail before the scheduler
+// is reported unhealthy and failures are escalated to a Warn
+const failureWarnThreshold = 3
+
+const (
+	// DefaultCheckPendingInterval applies when async.check_pending_interval is
+	// missing, unparsable or not positive
+	DefaultCheckPendingInterval = 5 * time.Second
+
+	// DefaultCheckPendingMaxInFlight applies when async.check_pending_max_in_flight
+	// is missing or not positive
+	DefaultCheckPendingMaxInFlight = 1
+)
+
+// EnqueueStats summarizes enqueue outcomes since the scheduler started
+type EnqueueStats struct {
+	Succeeded           int64     `json:"succeeded"`
+	Failed              int64     `json:"failed"`
+	ConsecutiveFailures int64     `json:"consecutive_failures"`
+	LastSuccess         time.Time `json:"last_success"`
+	LastError           string    `json:"last_error,omitempty"`
+	Skipped             int64     `json:"skipped"`
 }
 
-func NewCheckPendingOrderScheduler(redisAddr string, logger xcomp.Logger) *CheckPendingOrderScheduler {
-	redisOpt := asynq.RedisClientOpt{Addr: redisAddr}
-	client := asynq.NewClient(redisOpt)
+type CheckPendingOrderScheduler struct {
+	enqueuer    *jobs.Enqueuer
+	inspector   *asynq.Inspector
+	logger      xcomp.Logger
+	metrics     *xcomp.Metrics
+	ticker      *time.Ticker
+	done        chan bool
+	interval    time.Duration
+	maxInFlight int
+
+	succeeded           atomic.Int64
+	failed              atomic.Int64
+	skipped             atomic.Int64
+	consecutiveFailures atomic.Int64
+	lastSuccess         atomic.Int64
+	lastErrorMu         sync.Mutex
+	lastError           string
+}
 
+// NewCheckPendingOrderScheduler reads async.check_pending_interval and
+// async.check_pending_max_in_flight from cfg. A nil inspector disables the
+// in-flight guard.
+func NewCheckPendingOrderScheduler(
+	cfg *xcomp.ConfigService,
+	enqueuer *jobs.Enqueuer,
+	inspector *asynq.Inspector,
+	logger xcomp.Logger,
+) *CheckPendingOrderScheduler {
 	return &CheckPendingOrderScheduler{
-		client: *client,
-		logger: logger,
-		done:   make(chan bool),
+		enqueuer:    enqueuer,
+		inspector:   inspector,
+		logger:      logger,
+		done:        make(chan bool),
+		interval:    CheckPendingInterval(cfg),
+		maxInFlight: checkPendingMaxInFlight(cfg),
+	}
+}
+
+// CheckPendingInterval returns the configured tick interval, falling back to
+// DefaultCheckPendingInterval for missing, invalid or non-positive values
+func CheckPendingInterval(cfg *xcomp.ConfigService) time.Duration {
+	if cfg == nil {
+		return DefaultCheckPendingInterval
+	}
+	interval := cfg.GetDuration("async.check_pending_interval", DefaultCheckPendingInterval)
+	if interval <= 0 {
+		return DefaultCheckPendingInterval
+	}
+	return interval
+}
+
+func checkPendingMaxInFlight(cfg *xcomp.ConfigService) int {
+	if cfg == nil {
+		return DefaultCheckPendingMaxInFlight
+	}
+	maxInFlight := cfg.GetInt("async.check_pending_max_in_flight", DefaultCheckPendingMaxInFlight)
+	if maxInFlight <= 0 {
+		return DefaultCheckPendingMaxInFlight
+	}
+	return maxInFlight
+}
+
+// Interval returns how often the scheduler enqueues
+func (s *CheckPendingOrderScheduler) Interval() time.Duration {
+	return s.interval
+}
+
+// SetMetrics reports enqueue outcomes as scheduler_enqueue_total{job,result} counters
+func (s *CheckPendingOrderScheduler) SetMetrics(metrics *xcomp.Metrics) {
+	s.metrics = metrics
+}
+
+func (s *CheckPendingOrderScheduler) Stats() EnqueueStats {
+	stats := EnqueueStats{
+		Succeeded:           s.succeeded.Load(),
+		Failed:              s.failed.Load(),
+		ConsecutiveFailures: s.consecutiveFailures.Load(),
+		Skipped:             s.skipped.Load(),
+	}
+	if lastSuccess := s.lastSuccess.Load(); lastSuccess > 0 {
+		stats.LastSuccess = time.Unix(0, lastSuccess)
+	}
+
+	s.lastErrorMu.Lock()
+	stats.LastError = s.lastError
+	s.lastErrorMu.Unlock()
+
+	return stats
+}
+
+// HealthCheck fails while enqueues keep failing, usually because Redis is down
+func (s *CheckPendingOrderScheduler) HealthCheck(ctx context.Context) error {
+	stats := s.Stats()
+	if stats.ConsecutiveFailures >= failureWarnThreshold {
+		return fmt.Errorf("%d consecutive enqueue failures: %s", stats.ConsecutiveFailures, stats.LastError)
 	}
+	return nil
 }
 
 func (s *CheckPendingOrderScheduler) Start(ctx context.Context) error {
-	s.logger.Info("Starting CheckPendingOrderScheduler")
+	s.logger.Info("Starting CheckPendingOrderScheduler",
+		xcomp.Field("interval", s.interval),
+		xcomp.Field("max_in_flight", s.maxInFlight))
 
-	s.ticker = time.NewTicker(5 * time.Second)
+	s.ticker = time.NewTicker(s.interval)
 
 	go func() {
 		for {
@@ -43,10 +154,10 @@ func (s *CheckPendingOrderScheduler) Start(ctx context.Context) error {
 				s.logger.Info("CheckPendingOrderScheduler stopped")
 				return
 			case <-s.ticker.C:
-				if err := s.enqueueCheckPendingOrderJob(); err != nil {
-					s.logger.Error("Failed to enqueue check pending order job",
-						xcomp.Field("error", err))
+				if s.saturated() {
+					continue
 				}
+				s.recordEnqueue(s.enqueueCheckPendingOrderJob())
 			}
 		}
 	}()
@@ -62,18 +173,74 @@ func (s *CheckPendingOrderScheduler) Stop() {
 	}
 
 	close(s.done)
-	s.client.Close()
+	s.enqueuer.Close()
+	if s.inspector != nil {
+		s.inspector.Close()
+	}
 }
 
-func (s *CheckPendingOrderScheduler) enqueueCheckPendingOrderJob() error {
-	job := jobs.NewCheckPendingOrderJob()
-	payload, err := job.Payload()
+// saturated reports whether earlier jobs are still waiting or running, in
+// which case this tick is skipped so a lagging worker isn't buried in
+// duplicates. An unreadable queue doesn't block enqueuing.
+func (s *CheckPendingOrderScheduler) saturated() bool {
+	if s.inspector == nil {
+		return false
+	}
+
+	queue := s.enqueuer.QueueFor(jobs.TypeCheckPendingOrder)
+	info, err := s.inspector.GetQueueInfo(queue)
 	if err != nil {
-		return err
+		if !errors.Is(err, asynq.ErrQueueNotFound) {
+			s.logger.Warn("Failed to read queue depth, enqueuing anyway",
+				xcomp.Field("queue", queue),
+				xcomp.Err(err))
+		}
+		return false
+	}
+
+	inFlight := info.Pending + info.Active + info.Retry
+	if inFlight < s.maxInFlight {
+		return false
 	}
 
-	task := asynq.NewTask(jobs.TypeCheckPendingOrder, payload)
-	info, err := s.client.Enqueue(task)
+	s.skipped.Add(1)
+	s.metrics.Counter("scheduler_enqueue_total", "job", jobs.TypeCheckPendingOrder, "result", "skipped").Inc()
+	s.logger.Debug("Skipping check pending order job, previous jobs still in flight",
+		xcomp.Field("queue", queue),
+		xcomp.Field("in_flight", inFlight))
+	return true
+}
+
+func (s *CheckPendingOrderScheduler) recordEnqueue(err error) {
+	if err == nil {
+		s.succeeded.Add(1)
+		s.consecutiveFailures.Store(0)
+		s.lastSuccess.Store(time.Now().UnixNano())
+		s.metrics.Counter("scheduler_enqueue_total", "job", jobs.TypeCheckPendingOrder, "result", "success").Inc()
+		return
+	}
+
+	s.failed.Add(1)
+	consecutive := s.consecutiveFailures.Add(1)
+	s.metrics.Counter("scheduler_enqueue_total", "job", jobs.TypeCheckPendingOrder, "result", "failure").Inc()
+
+	s.lastErrorMu.Lock()
+	s.lastError = err.Error()
+	s.lastErrorMu.Unlock()
+
+	s.logger.Error("Failed to enqueue check pending order job",
+		xcomp.Err(err))
+
+	if consecutive >= failureWarnThreshold {
+		s.logger.Warn("Check pending order job enqueue failing repeatedly",
+			xcomp.Field("consecutive_failures", consecutive),
+			xcomp.Field("last_success", s.Stats().LastSuccess))
+	}
+}
+
+func (s *CheckPendingOrderScheduler) enqueueCheckPendingOrderJob() error {
+	job := jobs.NewCheckPendingOrderJob()
+	info, err := jobs.Enqueue(s.enqueuer, jobs.TypeCheckPendingOrder, job)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,64 @@
+package schedulers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"xcomp"
+)
+
+func newAsyncConfig(t *testing.T, asyncSection string) *xcomp.ConfigService {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("async:\n"+asyncSection), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return xcomp.NewConfigService(path)
+}
+
+func TestCheckPendingIntervalFallsBackToDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		want    time.Duration
+	}{
+		{name: "configured", setting: "  check_pending_interval: 2s\n", want: 2 * time.Second},
+		{name: "bare seconds", setting: "  check_pending_interval: 30\n", want: 30 * time.Second},
+		{name: "missing", setting: "  enabled: true\n", want: DefaultCheckPendingInterval},
+		{name: "zero", setting: "  check_pending_interval: 0s\n", want: DefaultCheckPendingInterval},
+		{name: "negative", setting: "  check_pending_interval: -5s\n", want: DefaultCheckPendingInterval},
+		{name: "unparsable", setting: "  check_pending_interval: soon\n", want: DefaultCheckPendingInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewCheckPendingOrderScheduler(newAsyncConfig(t, tt.setting), nil, nil, xcomp.NewDevelopmentLogger())
+			if got := scheduler.Interval(); got != tt.want {
+				t.Fatalf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := CheckPendingInterval(nil); got != DefaultCheckPendingInterval {
+		t.Fatalf("CheckPendingInterval(nil) = %v, want %v", got, DefaultCheckPendingInterval)
+	}
+}
+
+func TestCheckPendingMaxInFlightFallsBackToDefault(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    int
+	}{
+		{setting: "  check_pending_max_in_flight: 3\n", want: 3},
+		{setting: "  check_pending_max_in_flight: 0\n", want: DefaultCheckPendingMaxInFlight},
+		{setting: "  check_pending_max_in_flight: -1\n", want: DefaultCheckPendingMaxInFlight},
+		{setting: "  check_pending_max_in_flight: many\n", want: DefaultCheckPendingMaxInFlight},
+		{setting: "  enabled: true\n", want: DefaultCheckPendingMaxInFlight},
+	}
+	for _, tt := range tests {
+		if got := checkPendingMaxInFlight(newAsyncConfig(t, tt.setting)); got != tt.want {
+			t.Errorf("checkPendingMaxInFlight(%q) = %d, want %d", tt.setting, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	"xcomp"
+)
+
+const (
+	minServerTimeout = time.Second
+	minBodyLimit     = 1 << 10
+)
+
+// serverLimits are the Fiber settings that protect the server from slow or
+// oversized clients. A zero timeout would disable it in fasthttp and let idle or
+// slow connections pile up, so values below the minimums are raised with a warning.
+type serverLimits struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	BodyLimit    int
+	Concurrency  int
+}
+
+func loadServerLimits(configService *xcomp.ConfigService, logger xcomp.Logger) serverLimits {
+	return serverLimits{
+		ReadTimeout:  serverTimeout(configService, logger, "server.read_timeout_seconds", 30),
+		WriteTimeout: serverTimeout(configService, logger, "server.write_timeout_seconds", 30),
+		IdleTimeout:  serverTimeout(configService, logger, "server.idle_timeout_seconds", 60),
+		BodyLimit:    atLeast(configService, logger, "server.body_limit_bytes", 4<<20, minBodyLimit),
+		Concurrency:  atLeast(configService, logger, "server.concurrency", 256*1024, 1),
+	}
+}
+
+func serverTimeout(configService *xcomp.ConfigService, logger xcomp.Logger, key string, defaultSeconds int) time.Duration {
+	timeout := configService.GetDuration(key, time.Duration(defaultSeconds)*time.Second)
+	if timeout >= minServerTimeout {
+		return timeout
+	}
+
+	if logger != nil {
+		logger.Warn("Server timeout disabled or too low, using the minimum instead",
+			xcomp.String("key", key),
+			xcomp.Duration("configured", timeout),
+			xcomp.Duration("minimum", minServerTimeout))
+	}
+	return minServerTimeout
+}
+
+func atLeast(configService *xcomp.ConfigService, logger xcomp.Logger, key string, defaultValue, minimum int) int {
+	value := configService.GetInt(key, defaultValue)
+	if value >= minimum {
+		return value
+	}
+
+	if logger != nil {
+		logger.Warn("Server limit below minimum, using the minimum instead",
+			xcomp.String("key", key),
+			xcomp.Int("configured", value),
+			xcomp.Int("minimum", minimum))
+	}
+	return minimum
+}
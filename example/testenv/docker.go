@@ -0,0 +1,62 @@
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// dockerContainer is a throwaway container started with --rm, so stopping it removes it
+type dockerContainer struct {
+	id string
+}
+
+// startContainer runs image detached, publishing port on a random localhost port,
+// and returns the container with the host address it is reachable on
+func startContainer(ctx context.Context, image, port string, env map[string]string, args ...string) (*dockerContainer, string, error) {
+	runArgs := []string{"run", "-d", "--rm", "-p", "127.0.0.1::" + port}
+	for key, value := range env {
+		runArgs = append(runArgs, "-e", key+"="+value)
+	}
+	runArgs = append(runArgs, image)
+	runArgs = append(runArgs, args...)
+
+	out, err := docker(ctx, runArgs...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to start %s: %w", image, err)
+	}
+	container := &dockerContainer{id: out}
+
+	addr, err := docker(ctx, "port", container.id, port+"/tcp")
+	if err != nil {
+		container.stop()
+		return nil, "", fmt.Errorf("failed to resolve published port of %s: %w", image, err)
+	}
+	// docker port may list an IPv6 binding as well; the first line is enough
+	addr, _, _ = strings.Cut(addr, "\n")
+
+	return container, addr, nil
+}
+
+func (c *dockerContainer) stop() {
+	_, _ = docker(context.Background(), "stop", "-t", "1", c.id)
+}
+
+// dockerAvailable reports whether a docker daemon can be reached
+func dockerAvailable(ctx context.Context) bool {
+	_, err := docker(ctx, "info", "--format", "{{.ServerVersion}}")
+	return err == nil
+}
+
+func docker(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
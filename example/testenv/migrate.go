@@ -0,0 +1,62 @@
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// applyMigrations runs the "-- +goose Up" section of every migration in dir, in file
+// name order, the same order goose uses. Statement markers are dropped because each
+// section is sent as one multi-statement exec.
+func applyMigrations(ctx context.Context, pool *pgxpool.Pool, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", dir)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		if _, err := pool.Exec(ctx, gooseUp(string(data))); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+func gooseUp(migration string) string {
+	var up strings.Builder
+	inUp := false
+	for _, line := range strings.Split(migration, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Up"):
+			inUp = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			inUp = false
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose"):
+			continue
+		}
+		if inUp {
+			up.WriteString(line)
+			up.WriteString("\n")
+		}
+	}
+	return up.String()
+}
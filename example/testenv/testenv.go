@@ -0,0 +1,271 @@
+// Package testenv boots throwaway Postgres and Redis instances for integration tests
+// and hands back an xcomp container wired with the business modules on top of them.
+//
+// Inside a test:
+//
+//	env := testenv.New(t)
+//	products, err := xcomp.ResolveByType[interfaces.ProductService](env.Container)
+//
+// By default the databases run in docker containers that are removed when the test
+// finishes. CI jobs that already provide service containers can point the harness at
+// them with TEST_DATABASE_URL and TEST_REDIS_URL instead.
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"example/infrastructure/async"
+	"example/infrastructure/database"
+	"example/modules/customer"
+	"example/modules/order"
+	"example/modules/product"
+
+	"xcomp"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DatabaseURLEnv = "TEST_DATABASE_URL"
+	RedisURLEnv    = "TEST_REDIS_URL"
+)
+
+type Options struct {
+	// MigrationsDir defaults to the example's migrations directory
+	MigrationsDir string
+	PostgresImage string
+	RedisImage    string
+	// StartTimeout bounds booting the containers and waiting for them to accept connections
+	StartTimeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MigrationsDir == "" {
+		_, file, _, _ := runtime.Caller(0)
+		o.MigrationsDir = filepath.Join(filepath.Dir(file), "..", "migrations")
+	}
+	if o.PostgresImage == "" {
+		o.PostgresImage = "postgres:16.2-alpine"
+	}
+	if o.RedisImage == "" {
+		o.RedisImage = "redis:7.2-alpine"
+	}
+	if o.StartTimeout <= 0 {
+		o.StartTimeout = 60 * time.Second
+	}
+	return o
+}
+
+// Env is a migrated database, an empty Redis and a container wired against them
+type Env struct {
+	DatabaseURL string
+	RedisURL    string
+	DB          *pgxpool.Pool
+	Redis       *redis.Client
+	Container   *xcomp.Container
+
+	containers []*dockerContainer
+}
+
+// New starts an environment for t and closes it when t finishes. The test is
+// skipped when Postgres or Redis has no TEST_* URL and docker isn't there to
+// start it.
+func New(t testing.TB) *Env {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("integration test skipped in -short mode")
+	}
+
+	ctx := context.Background()
+	if missing := unsetURLs(); len(missing) > 0 && !dockerAvailable(ctx) {
+		t.Skipf("docker is not available and %s is not set", strings.Join(missing, ", "))
+	}
+
+	env, err := Start(ctx, Options{})
+	if err != nil {
+		t.Fatalf("failed to start test environment: %v", err)
+	}
+	t.Cleanup(env.Close)
+	return env
+}
+
+// unsetURLs lists the TEST_* variables that are empty, i.e. the services docker
+// would have to provide
+func unsetURLs() []string {
+	var missing []string
+	for _, name := range []string{DatabaseURLEnv, RedisURLEnv} {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Start boots Postgres and Redis (or connects to the TEST_* URLs), applies the
+// migrations and builds the container. Call Close when done.
+func Start(ctx context.Context, opts Options) (*Env, error) {
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.StartTimeout)
+	defer cancel()
+
+	env := &Env{
+		DatabaseURL: os.Getenv(DatabaseURLEnv),
+		RedisURL:    os.Getenv(RedisURLEnv),
+	}
+	if err := env.start(ctx, opts); err != nil {
+		env.Close()
+		return nil, err
+	}
+	return env, nil
+}
+
+func (e *Env) start(ctx context.Context, opts Options) error {
+	if e.DatabaseURL == "" {
+		container, addr, err := startContainer(ctx, opts.PostgresImage, "5432", map[string]string{
+			"POSTGRES_DB":       "test",
+			"POSTGRES_USER":     "test",
+			"POSTGRES_PASSWORD": "test",
+		})
+		if err != nil {
+			return err
+		}
+		e.containers = append(e.containers, container)
+		e.DatabaseURL = fmt.Sprintf("postgres://test:test@%s/test?sslmode=disable", addr)
+	}
+
+	if e.RedisURL == "" {
+		container, addr, err := startContainer(ctx, opts.RedisImage, "6379", nil)
+		if err != nil {
+			return err
+		}
+		e.containers = append(e.containers, container)
+		e.RedisURL = fmt.Sprintf("redis://%s/0", addr)
+	}
+
+	db, err := pgxpool.New(ctx, e.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to create database pool: %w", err)
+	}
+	e.DB = db
+	if err := waitReady(ctx, "postgres", db.Ping); err != nil {
+		return err
+	}
+
+	redisOptions, err := redis.ParseURL(e.RedisURL)
+	if err != nil {
+		return fmt.Errorf("invalid redis url: %w", err)
+	}
+	e.Redis = redis.NewClient(redisOptions)
+	if err := waitReady(ctx, "redis", func(ctx context.Context) error {
+		return e.Redis.Ping(ctx).Err()
+	}); err != nil {
+		return err
+	}
+
+	if err := applyMigrations(ctx, e.DB, opts.MigrationsDir); err != nil {
+		return err
+	}
+
+	container, err := e.buildContainer()
+	if err != nil {
+		return err
+	}
+	e.Container = container
+	return nil
+}
+
+// buildContainer registers the modules main.go does, with the infrastructure
+// services pointed at this environment. HTTP transport lives in package main and
+// is left out; the async module is registered but never started.
+func (e *Env) buildContainer() (*xcomp.Container, error) {
+	container := xcomp.NewContainer()
+	container.SetStrictInject(true)
+
+	infrastructure := xcomp.NewModule().
+		AddService("ConfigService", xcomp.NewConfigService()).
+		AddService("Logger", xcomp.NewDevelopmentLogger()).
+		AddService("DatabaseConnection", e.DB).
+		AddService("RedisClient", e.Redis).
+		AddFactory("TxManager", func(c *xcomp.Container) any {
+			txManager := &database.TxManager{}
+			c.MustInject(txManager)
+			return txManager
+		}).
+		AddFactory("IdempotencyStore", func(c *xcomp.Container) any {
+			store := &database.RedisIdempotencyStore{}
+			c.MustInject(store)
+			return store
+		}).
+		// In-process delivery keeps events synchronous, so tests can assert on them directly
+		AddService("EventBus", xcomp.NewInProcessEventBus()).
+		Build()
+
+	err := container.RegisterModules(
+		infrastructure,
+		xcomp.NewMetricsModule(),
+		xcomp.NewSerializerModule(),
+		xcomp.NewValidatorModule(),
+		product.CreateProductModule(),
+		order.NewOrderModule(),
+		customer.CreateCustomerModule(),
+		async.CreateAsyncModule(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register modules: %w", err)
+	}
+	return container, nil
+}
+
+// Reset empties every table and Redis so tests sharing an Env start from scratch
+func (e *Env) Reset(ctx context.Context) error {
+	if _, err := e.DB.Exec(ctx, "TRUNCATE order_items, orders, customers, products RESTART IDENTITY CASCADE"); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	if err := e.Redis.FlushDB(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to flush redis: %w", err)
+	}
+	return nil
+}
+
+// Close releases the connections and removes any containers that were started
+func (e *Env) Close() {
+	if e.Redis != nil {
+		e.Redis.Close()
+	}
+	if e.DB != nil {
+		e.DB.Close()
+	}
+	for _, container := range e.containers {
+		container.stop()
+	}
+	e.containers = nil
+}
+
+// waitReady retries ping until it succeeds or ctx expires; freshly started
+// containers take a moment before they accept connections
+func waitReady(ctx context.Context, name string, ping func(context.Context) error) error {
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err := ping(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s did not become ready: %w", name, err)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
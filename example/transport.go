@@ -3,24 +3,36 @@ package main
 import (
 	"example/controllers"
 	"xcomp"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 )
 
 func CreateTransportModule() xcomp.Module {
 	return xcomp.NewModule().
+		Named("http").
 		AddFactory("ProductController", func(c *xcomp.Container) any {
 			controller := &controllers.ProductController{}
-			c.Inject(controller)
+			c.MustInject(controller)
 			return controller
 		}).
 		AddFactory("OrderController", func(c *xcomp.Container) any {
 			controller := &controllers.OrderController{}
-			c.Inject(controller)
+			c.MustInject(controller)
 			return controller
 		}).
 		AddFactory("CustomerController", func(c *xcomp.Container) any {
 			controller := &controllers.CustomerController{}
-			c.Inject(controller)
+			c.MustInject(controller)
 			return controller
 		}).
+		AddFactory("JobAdminController", func(c *xcomp.Container) any {
+			// Archived jobs live in Redis; without it there is nothing to administer
+			redisClient, _ := c.Get("RedisClient").(*redis.Client)
+			if redisClient == nil {
+				return (*controllers.JobAdminController)(nil)
+			}
+			return controllers.NewJobAdminController(asynq.NewInspectorFromRedisClient(redisClient))
+		}).
 		Build()
 }
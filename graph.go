@@ -0,0 +1,87 @@
+package xcomp
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DependencyGraph maps each lazy singleton built so far to the services its
+// factory resolved, sorted. Edges are recorded as factories call Get, directly or
+// through Inject, so services never built have none; call it after startup, or
+// after resolving what you want to see. Module namespaces are included.
+func (c *Container) DependencyGraph() map[string][]string {
+	graph := make(map[string][]string)
+	c.mergeDependencies(graph)
+	for service := range graph {
+		sort.Strings(graph[service])
+	}
+	return graph
+}
+
+func (c *Container) mergeDependencies(graph map[string][]string) {
+	c.mutex.RLock()
+	namespaces := make([]*Container, 0, len(c.namespaces))
+	for _, namespace := range c.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	for service, dependencies := range c.dependencies {
+		for _, dependency := range dependencies {
+			graph[service] = appendUnique(graph[service], dependency)
+		}
+	}
+	c.mutex.RUnlock()
+
+	for _, namespace := range namespaces {
+		namespace.mergeDependencies(graph)
+	}
+}
+
+// ExportGraphviz writes the DependencyGraph in DOT format, e.g. for
+// `dot -Tsvg graph.dot -o graph.svg`
+func (c *Container) ExportGraphviz(w io.Writer) error {
+	graph := c.DependencyGraph()
+	services := make([]string, 0, len(graph))
+	for service := range graph {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	if _, err := fmt.Fprintln(w, "digraph xcomp {"); err != nil {
+		return err
+	}
+	for _, service := range services {
+		for _, dependency := range graph[service] {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", service, dependency); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// recordDependency adds an edge from the service the calling goroutine is
+// constructing, if any, to name
+func (c *Container) recordDependency(name string) {
+	dependent, ok := c.resolution.current()
+	if !ok || dependent == name {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.dependencies == nil {
+		c.dependencies = make(map[string][]string)
+	}
+	c.dependencies[dependent] = appendUnique(c.dependencies[dependent], name)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,58 @@
+package xcomp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraphRecordsFactoryLookups(t *testing.T) {
+	c, _ := newLoggerContainer()
+	module := NewModule().
+		AddService("Config", struct{}{}).
+		AddFactory("Repository", func(c *Container) any {
+			return c.Get("Config")
+		}).
+		AddFactory("Service", func(c *Container) any {
+			c.Get("Repository")
+			c.Get("Logger")
+			return struct{}{}
+		}).
+		Build()
+	if err := c.RegisterModule(module); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Get("Service")
+
+	want := map[string][]string{
+		"Repository": {"Config"},
+		"Service":    {"Logger", "Repository"},
+	}
+	if got := c.DependencyGraph(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DependencyGraph() = %v, want %v", got, want)
+	}
+
+	var dot strings.Builder
+	if err := c.ExportGraphviz(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range []string{`"Repository" -> "Config";`, `"Service" -> "Logger";`, `"Service" -> "Repository";`} {
+		if !strings.Contains(dot.String(), edge) {
+			t.Errorf("ExportGraphviz output lacks %s:\n%s", edge, dot.String())
+		}
+	}
+}
+
+func TestDependencyGraphIncludesModuleNamespaces(t *testing.T) {
+	c := NewContainer()
+	if err := c.RegisterModule(newExportingModule()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Get("OrderService")
+
+	if got := c.DependencyGraph()["OrderService"]; !reflect.DeepEqual(got, []string{"OrderRepository"}) {
+		t.Fatalf(`DependencyGraph()["OrderService"] = %v, want [OrderRepository]`, got)
+	}
+}
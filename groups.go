@@ -0,0 +1,76 @@
+package xcomp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// groupTagPrefix marks an inject tag that collects every member of a group into a
+// slice field, e.g. `inject:"group:healthchecks"` on a []HealthCheckProvider
+const groupTagPrefix = "group:"
+
+// AddToGroup registers a lazy singleton as a member of group. Members have no name of
+// their own; they are resolved together, in registration order, by GetGroup or an
+// `inject:"group:<name>"` slice field.
+func (c *Container) AddToGroup(group string, factory func(*Container) any) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.addToGroupLocked(group, &lazyService{factory: factory, container: c})
+}
+
+// addToGroupLocked stores service under a generated name so members are resolved and
+// closed like any other service; the caller holds the write lock
+func (c *Container) addToGroupLocked(group string, service any) string {
+	if c.groups == nil {
+		c.groups = make(map[string][]string)
+	}
+	name := fmt.Sprintf("%s%s#%d", groupTagPrefix, group, len(c.groups[group]))
+	c.groups[group] = append(c.groups[group], name)
+	c.setLocked(name, service)
+	return name
+}
+
+// GetGroup resolves every member of group in registration order, skipping members
+// that resolve to nil. An unknown group has no members. In a scope, the parent's
+// members come first.
+func (c *Container) GetGroup(group string) []any {
+	var members []any
+	if c.parent != nil {
+		members = c.parent.GetGroup(group)
+	}
+
+	c.mutex.RLock()
+	names := append([]string(nil), c.groups[group]...)
+	c.mutex.RUnlock()
+
+	for _, name := range names {
+		if member := c.Get(name); member != nil {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// groupValue builds a slice of sliceType holding every member of group
+func (c *Container) groupValue(group string, sliceType reflect.Type) (reflect.Value, error) {
+	if sliceType.Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("group '%s' can only be injected into a slice, not %s", group, sliceType)
+	}
+
+	elemType := sliceType.Elem()
+	members := c.GetGroup(group)
+	slice := reflect.MakeSlice(sliceType, 0, len(members))
+	for i, member := range members {
+		memberValue := reflect.ValueOf(member)
+		if !memberValue.Type().AssignableTo(elemType) {
+			return reflect.Value{}, fmt.Errorf("member %d of group '%s' has type %T, not assignable to %s", i, group, member, elemType)
+		}
+		slice = reflect.Append(slice, memberValue)
+	}
+	return slice, nil
+}
+
+func parseGroupTag(name string) (group string, ok bool) {
+	return strings.CutPrefix(name, groupTagPrefix)
+}
@@ -0,0 +1,122 @@
+package xcomp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthCheck reports a dependency as down by returning an error
+type HealthCheck func(ctx context.Context) error
+
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+type HealthResult struct {
+	Name     string        `json:"name"`
+	Status   HealthStatus  `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport aggregates every check; Status is down if any check is down
+type HealthReport struct {
+	Status HealthStatus   `json:"status"`
+	Checks []HealthResult `json:"checks"`
+}
+
+func (r HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthChecker runs registered dependency checks concurrently and aggregates the results
+type HealthChecker struct {
+	mu     sync.RWMutex
+	checks []namedHealthCheck
+}
+
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{}
+}
+
+func (h *HealthChecker) GetServiceName() string {
+	return "HealthChecker"
+}
+
+func (h *HealthChecker) Register(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedHealthCheck{name: name, check: check})
+}
+
+// Check runs every check and returns results in registration order.
+// A check still running when ctx is done is reported down with ctx's error.
+func (h *HealthChecker) Check(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := append([]namedHealthCheck(nil), h.checks...)
+	h.mu.RUnlock()
+
+	report := HealthReport{
+		Status: HealthStatusUp,
+		Checks: make([]HealthResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check namedHealthCheck) {
+			defer wg.Done()
+			report.Checks[i] = runHealthCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == HealthStatusDown {
+			report.Status = HealthStatusDown
+		}
+	}
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, check namedHealthCheck) HealthResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health check panicked: %v", r)
+			}
+		}()
+		done <- check.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := HealthResult{
+		Name:     check.name,
+		Status:   HealthStatusUp,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultHealthCheckTimeout bounds Container.HealthCheck when ctx has no deadline
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheckable is implemented by services that can report whether they work,
+// e.g. by pinging the server behind them. Container.HealthCheck reports them under
+// their registration name.
+type HealthCheckable interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// pinger covers clients checked with Ping, e.g. *pgxpool.Pool
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthReport runs the health check of every service the container has built,
+// and of the instances registered in it, concurrently. Services implementing
+// HealthCheckable are checked, as are those with a Ping(ctx) error method. Lazy
+// singletons that were never resolved are not built just to be checked.
+func (c *Container) HealthReport(ctx context.Context) HealthReport {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+		defer cancel()
+	}
+
+	checker := NewHealthChecker()
+	for _, check := range c.healthChecks() {
+		checker.Register(check.name, check.check)
+	}
+	return checker.Check(ctx)
+}
+
+// HealthCheck is HealthReport as a map of service name to error, nil for the
+// services that are up
+func (c *Container) HealthCheck(ctx context.Context) map[string]error {
+	report := c.HealthReport(ctx)
+	results := make(map[string]error, len(report.Checks))
+	for _, result := range report.Checks {
+		results[result.Name] = nil
+		if result.Status == HealthStatusDown {
+			results[result.Name] = errors.New(result.Error)
+		}
+	}
+	return results
+}
+
+func (c *Container) healthChecks() []namedHealthCheck {
+	c.mutex.RLock()
+	var checks []namedHealthCheck
+	var namespaces []*Container
+	for _, name := range c.order {
+		if namespace, ok := c.namespaces[name]; ok {
+			namespaces = append(namespaces, namespace)
+			continue
+		}
+
+		service := c.services[name]
+		if lazy, ok := service.(*lazyService); ok {
+			instance, done := lazy.resolved()
+			if !done {
+				continue
+			}
+			service = instance
+		}
+		if isNil(service) {
+			continue
+		}
+
+		switch s := service.(type) {
+		case HealthCheckable:
+			checks = append(checks, namedHealthCheck{name: name, check: s.HealthCheck})
+		case pinger:
+			checks = append(checks, namedHealthCheck{name: name, check: s.Ping})
+		}
+	}
+	c.mutex.RUnlock()
+
+	for _, namespace := range namespaces {
+		checks = append(checks, namespace.healthChecks()...)
+	}
+	return checks
+}
+
+// NewHealthHandler serves the container's HealthReport as JSON, with status 200
+// when every check is up and 503 otherwise, for readiness probes
+func NewHealthHandler(c *Container, timeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		checkCtx, cancel := context.WithTimeout(ctx.UserContext(), timeout)
+		defer cancel()
+
+		report := c.HealthReport(checkCtx)
+		status := fiber.StatusOK
+		if !report.Healthy() {
+			status = fiber.StatusServiceUnavailable
+		}
+		return ctx.Status(status).JSON(report)
+	}
+}
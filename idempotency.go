@@ -0,0 +1,246 @@
+package xcomp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IdempotentResponse is the captured response replayed for duplicate requests
+type IdempotentResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyStore persists captured responses and in-flight locks, typically in Redis
+type IdempotencyStore interface {
+	// Get returns nil without error when no response is stored for key
+	Get(ctx context.Context, key string) (*IdempotentResponse, error)
+	Set(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+	// Lock claims key for an in-flight request, recording token as the holder,
+	// and reports whether it was acquired
+	Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	// Unlock releases key only while token still holds it, so a request whose
+	// lock expired can't release one another request has since taken
+	Unlock(ctx context.Context, key, token string) error
+}
+
+type IdempotencyConfig struct {
+	Store     IdempotencyStore
+	Header    string
+	Methods   []string
+	TTL       time.Duration
+	LockTTL   time.Duration
+	KeyPrefix string
+	Logger    Logger
+}
+
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+func (cfg IdempotencyConfig) withDefaults() IdempotencyConfig {
+	if cfg.Header == "" {
+		cfg.Header = "Idempotency-Key"
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{fiber.MethodPost, fiber.MethodPatch}
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = 30 * time.Second
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "idempotency:"
+	}
+	return cfg
+}
+
+// NewIdempotencyMiddleware replays the first response for a repeated Idempotency-Key.
+// Requests without the header, or using a method outside cfg.Methods, pass through.
+// A duplicate arriving while the first is still in flight gets 409 Conflict.
+// Errors returned by the handler are rendered with the app's ErrorHandler before
+// capture, so 4xx responses replay too; server errors (5xx) are not captured so
+// the client can retry with the same key.
+func NewIdempotencyMiddleware(cfg IdempotencyConfig) fiber.Handler {
+	cfg = cfg.withDefaults()
+
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, method := range cfg.Methods {
+		methods[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Store == nil || !methods[c.Method()] {
+			return c.Next()
+		}
+
+		idempotencyKey := c.Get(cfg.Header)
+		if idempotencyKey == "" {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		storeKey := cfg.KeyPrefix + c.Method() + ":" + c.Path() + ":" + idempotencyKey
+		lockKey := storeKey + ":lock"
+
+		cached, err := cfg.Store.Get(ctx, storeKey)
+		if err != nil {
+			cfg.logWarn("Idempotency lookup failed, processing request normally", idempotencyKey, err)
+			return c.Next()
+		}
+		if cached != nil {
+			return replayIdempotentResponse(c, cached)
+		}
+
+		token := newLockToken()
+		acquired, err := cfg.Store.Lock(ctx, lockKey, token, cfg.LockTTL)
+		if err != nil {
+			cfg.logWarn("Idempotency lock failed, processing request normally", idempotencyKey, err)
+			return c.Next()
+		}
+		if !acquired {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "Request in progress",
+				"message": "A request with this idempotency key is already being processed",
+			})
+		}
+		defer func() {
+			if err := cfg.Store.Unlock(ctx, lockKey, token); err != nil {
+				cfg.logWarn("Failed to release idempotency lock", idempotencyKey, err)
+			}
+		}()
+
+		// The first request may have finished between the lookup and the lock
+		cached, err = cfg.Store.Get(ctx, storeKey)
+		if err != nil {
+			cfg.logWarn("Idempotency lookup failed, processing request normally", idempotencyKey, err)
+		} else if cached != nil {
+			return replayIdempotentResponse(c, cached)
+		}
+
+		if err := c.Next(); err != nil {
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			return nil
+		}
+
+		response := &IdempotentResponse{
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := cfg.Store.Set(ctx, storeKey, response, cfg.TTL); err != nil {
+			cfg.logWarn("Failed to store idempotent response", idempotencyKey, err)
+		}
+
+		return nil
+	}
+}
+
+// newLockToken identifies one request's hold on an idempotency lock
+func newLockToken() string {
+	if token := newRequestID(); token != "" {
+		return token
+	}
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func replayIdempotentResponse(c *fiber.Ctx, response *IdempotentResponse) error {
+	c.Set(IdempotencyReplayedHeader, "true")
+	if response.ContentType != "" {
+		c.Set(fiber.HeaderContentType, response.ContentType)
+	}
+	return c.Status(response.StatusCode).Send(response.Body)
+}
+
+func (cfg IdempotencyConfig) logWarn(msg string, key string, err error) {
+	if cfg.Logger != nil {
+		cfg.Logger.Warn(msg,
+			Field("idempotency_key", key),
+			Err(err))
+	}
+}
+
+type memoryIdempotencyEntry struct {
+	response  *IdempotentResponse
+	token     string
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore keeps responses and locks in process memory. It suits a
+// single instance without Redis; entries are not shared between instances.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *MemoryIdempotencyStore) GetServiceName() string {
+	return "IdempotencyStore"
+}
+
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.live(key)
+	if !ok {
+		return nil, nil
+	}
+	return entry.response, nil
+}
+
+func (s *MemoryIdempotencyStore) Set(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryIdempotencyEntry{response: response, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, held := s.live(key); held {
+		return false, nil
+	}
+	s.entries[key] = memoryIdempotencyEntry{token: token, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryIdempotencyStore) Unlock(ctx context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, held := s.live(key); held && entry.token == token {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// live returns the entry for key, dropping it if expired; callers hold mu
+func (s *MemoryIdempotencyStore) live(key string) (memoryIdempotencyEntry, bool) {
+	entry, ok := s.entries[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return memoryIdempotencyEntry{}, false
+	}
+	return entry, ok
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
@@ -0,0 +1,143 @@
+package xcomp
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newIdempotentApp(store IdempotencyStore, handler fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: FiberErrorHandler(nil)})
+	app.Use(NewIdempotencyMiddleware(IdempotencyConfig{Store: store}))
+	app.Post("/orders", handler)
+	return app
+}
+
+func postOrder(t *testing.T, app *fiber.App, key string) (int, string, bool) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodPost, "/orders", nil)
+	req.Header.Set("Idempotency-Key", key)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body), resp.Header.Get(IdempotencyReplayedHeader) == "true"
+}
+
+func TestIdempotencyReplaysSuccessfulResponse(t *testing.T) {
+	calls := 0
+	app := newIdempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": calls})
+	})
+
+	status, first, _ := postOrder(t, app, "k1")
+	replayStatus, replay, replayed := postOrder(t, app, "k1")
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if status != fiber.StatusCreated || replayStatus != status || replay != first || !replayed {
+		t.Fatalf("replay = %d %q (replayed %v), want %d %q", replayStatus, replay, replayed, status, first)
+	}
+}
+
+func TestIdempotencyReplaysRenderedClientError(t *testing.T) {
+	calls := 0
+	rejected := NewValidation("order total exceeds the limit")
+	app := newIdempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		calls++
+		return rejected
+	})
+
+	status, first, _ := postOrder(t, app, "k1")
+	if status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusUnprocessableEntity)
+	}
+	replayStatus, replay, replayed := postOrder(t, app, "k1")
+	if calls != 1 {
+		t.Fatalf("handler ran %d times for a replayed 4xx, want 1", calls)
+	}
+	if replayStatus != status || replay != first || !replayed {
+		t.Fatalf("replay = %d %q (replayed %v), want %d %q", replayStatus, replay, replayed, status, first)
+	}
+}
+
+func TestIdempotencyDoesNotCaptureServerErrors(t *testing.T) {
+	calls := 0
+	app := newIdempotentApp(NewMemoryIdempotencyStore(), func(c *fiber.Ctx) error {
+		calls++
+		return NewAppError(fiber.StatusServiceUnavailable, "unavailable", "try again")
+	})
+
+	postOrder(t, app, "k1")
+	postOrder(t, app, "k1")
+	if calls != 2 {
+		t.Fatalf("handler ran %d times, want a retry after a 5xx", calls)
+	}
+}
+
+// lateStore stores a response as the lock is taken, like a first request that
+// finishes between a duplicate's lookup and its lock
+type lateStore struct {
+	*MemoryIdempotencyStore
+	response *IdempotentResponse
+}
+
+func (s *lateStore) Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	storeKey := key[:len(key)-len(":lock")]
+	if err := s.Set(ctx, storeKey, s.response, time.Minute); err != nil {
+		return false, err
+	}
+	return s.MemoryIdempotencyStore.Lock(ctx, key, token, ttl)
+}
+
+func TestIdempotencyChecksStoreAgainAfterLocking(t *testing.T) {
+	store := &lateStore{
+		MemoryIdempotencyStore: NewMemoryIdempotencyStore(),
+		response:               &IdempotentResponse{StatusCode: fiber.StatusCreated, Body: []byte(`{"id":1}`)},
+	}
+	calls := 0
+	app := newIdempotentApp(store, func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	status, body, replayed := postOrder(t, app, "k1")
+	if calls != 0 {
+		t.Fatalf("handler ran %d times although a response was stored before the lock", calls)
+	}
+	if status != fiber.StatusCreated || body != `{"id":1}` || !replayed {
+		t.Fatalf("response = %d %q (replayed %v), want the stored one", status, body, replayed)
+	}
+}
+
+func TestMemoryIdempotencyStoreUnlockChecksToken(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := context.Background()
+
+	if ok, _ := store.Lock(ctx, "lock", "second", time.Minute); !ok {
+		t.Fatal("Lock on a free key failed")
+	}
+	// A request whose own lock expired must not release the current holder's
+	if err := store.Unlock(ctx, "lock", "first"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Lock(ctx, "lock", "third", time.Minute); ok {
+		t.Fatal("Unlock with a stale token released the lock")
+	}
+
+	if err := store.Unlock(ctx, "lock", "second"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Lock(ctx, "lock", "third", time.Minute); !ok {
+		t.Fatal("Unlock with the holder's token did not release the lock")
+	}
+}
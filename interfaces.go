@@ -1,5 +1,11 @@
 package xcomp
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
 type Injectable interface {
 	GetServiceName() string
 }
@@ -14,10 +20,31 @@ type Module interface {
 	GetImports() []Module
 }
 
+// NamedModule is a module that can be switched off by name, see Container.SetModuleFilter
+type NamedModule interface {
+	Module
+	GetName() string
+}
+
 type Provider struct {
 	Name    string
 	Factory func(*Container) any
 	Service any
+	// Condition, when set, is evaluated on first resolution; a provider whose condition
+	// fails gives way to the next provider registered under the same name
+	Condition func(*Container) bool
+	// Constructor is a function whose parameters are resolved from the container,
+	// see Container.RegisterConstructor; ParamNames optionally resolves them by name
+	Constructor any
+	ParamNames  []string
+	// Eager providers are built as soon as their module is registered, so that
+	// initialization failures surface at startup instead of on first use
+	Eager bool
+	// Group, when set, adds the provider to the named group instead of registering
+	// it under Name, see Container.AddToGroup
+	Group string
+	// namespace is set for providers of a module with exports, see ModuleBuilder.Export
+	namespace *moduleNamespace
 }
 
 func NewProvider(name string, factory func(*Container) any) Provider {
@@ -34,9 +61,21 @@ func NewServiceProvider(name string, service any) Provider {
 	}
 }
 
+func NewConstructorProvider(name string, constructor any, paramNames ...string) Provider {
+	return Provider{
+		Name:        name,
+		Constructor: constructor,
+		ParamNames:  paramNames,
+	}
+}
+
 type ModuleBuilder struct {
+	name      string
 	providers []Provider
 	imports   []Module
+	exports   []string
+	onStart   []ModuleHook
+	onStop    []ModuleHook
 }
 
 func NewModule() *ModuleBuilder {
@@ -46,6 +85,12 @@ func NewModule() *ModuleBuilder {
 	}
 }
 
+// Named gives the module a name so it can be enabled or disabled through the module filter
+func (mb *ModuleBuilder) Named(name string) *ModuleBuilder {
+	mb.name = name
+	return mb
+}
+
 func (mb *ModuleBuilder) AddProvider(provider Provider) *ModuleBuilder {
 	mb.providers = append(mb.providers, provider)
 	return mb
@@ -61,6 +106,39 @@ func (mb *ModuleBuilder) AddFactory(name string, factory func(*Container) any) *
 	return mb
 }
 
+// AddEagerFactory is AddFactory for services that should fail the registration
+// rather than the first request, such as database connections
+func (mb *ModuleBuilder) AddEagerFactory(name string, factory func(*Container) any) *ModuleBuilder {
+	provider := NewProvider(name, factory)
+	provider.Eager = true
+	mb.providers = append(mb.providers, provider)
+	return mb
+}
+
+// AddConstructor registers a typed constructor instead of a factory, e.g.
+// AddConstructor("OrderService", NewOrderService); see Container.RegisterConstructor
+func (mb *ModuleBuilder) AddConstructor(name string, constructor any, paramNames ...string) *ModuleBuilder {
+	mb.providers = append(mb.providers, NewConstructorProvider(name, constructor, paramNames...))
+	return mb
+}
+
+// AddToGroup adds a lazy singleton to group, e.g. one of several health checks
+// collected by an `inject:"group:healthchecks"` slice field
+func (mb *ModuleBuilder) AddToGroup(group string, factory func(*Container) any) *ModuleBuilder {
+	mb.providers = append(mb.providers, Provider{Group: group, Factory: factory})
+	return mb
+}
+
+// AddFactoryIf registers factory only for containers where condition holds, e.g. a
+// Redis-backed implementation when redis.enabled is true. Follow it with further
+// providers under the same name as alternatives; the first whose condition holds wins.
+func (mb *ModuleBuilder) AddFactoryIf(name string, condition func(*Container) bool, factory func(*Container) any) *ModuleBuilder {
+	provider := NewProvider(name, factory)
+	provider.Condition = condition
+	mb.providers = append(mb.providers, provider)
+	return mb
+}
+
 func (mb *ModuleBuilder) Import(module Module) *ModuleBuilder {
 	mb.imports = append(mb.imports, module)
 	return mb
@@ -68,14 +146,32 @@ func (mb *ModuleBuilder) Import(module Module) *ModuleBuilder {
 
 func (mb *ModuleBuilder) Build() Module {
 	return &BasicModule{
+		name:      mb.name,
 		providers: mb.providers,
 		imports:   mb.imports,
+		exports:   mb.exports,
+		onStart:   mb.onStart,
+		onStop:    mb.onStop,
 	}
 }
 
 type BasicModule struct {
+	name      string
 	providers []Provider
 	imports   []Module
+	exports   []string
+	onStart   []ModuleHook
+	onStop    []ModuleHook
+}
+
+// GetExports returns nil unless the module was built with Export, meaning every
+// provider is visible
+func (bm *BasicModule) GetExports() []string {
+	return bm.exports
+}
+
+func (bm *BasicModule) GetName() string {
+	return bm.name
 }
 
 func (bm *BasicModule) GetProviders() []Provider {
@@ -86,20 +182,371 @@ func (bm *BasicModule) GetImports() []Module {
 	return bm.imports
 }
 
+// SetModuleFilter decides which named modules are registered. A module the filter
+// rejects is skipped together with its imports; unnamed modules are always registered.
+func (c *Container) SetModuleFilter(enabled func(name string) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.moduleFilter = enabled
+}
+
+// ModuleEnabled reports whether the module filter allows the named module
+func (c *Container) ModuleEnabled(name string) bool {
+	c.mutex.RLock()
+	filter := c.moduleFilter
+	c.mutex.RUnlock()
+
+	return name == "" || filter == nil || filter(name)
+}
+
+// ModuleEnabledFromConfig enables a module unless modules.<name>.enabled is false
+func ModuleEnabledFromConfig(config *ConfigService) func(name string) bool {
+	return func(name string) bool {
+		return config.GetBool("modules."+name+".enabled", true)
+	}
+}
+
+// RegistrationWarnings lists providers that were ignored or replaced because their
+// name was already taken; log them once the logger is available
+func (c *Container) RegistrationWarnings() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]string(nil), c.warnings...)
+}
+
 func (c *Container) RegisterModule(module Module) error {
+	return c.RegisterModules(module)
+}
+
+// RegisterOptions controls how RegisterModulesWithOptions treats provider names
+// that are already taken
+type RegisterOptions struct {
+	// Strict fails the registration on the first duplicate provider name, whether
+	// within the modules or already in the container, instead of keeping the first
+	// provider and recording a warning
+	Strict bool
+}
+
+// DuplicateProviderError is returned by a strict registration that found a
+// provider name registered twice
+type DuplicateProviderError struct {
+	Name string
+	// First and Second describe the two registrations, e.g. "module 'product'"
+	First  string
+	Second string
+}
+
+func (e *DuplicateProviderError) Error() string {
+	return fmt.Sprintf("provider '%s' from %s is already registered by %s", e.Name, e.Second, e.First)
+}
+
+// RegisterModuleStrict is RegisterModule failing with a DuplicateProviderError on
+// the first provider name registered twice, leaving the container untouched
+func (c *Container) RegisterModuleStrict(module Module) error {
+	return c.RegisterModulesWithOptions(RegisterOptions{Strict: true}, module)
+}
+
+// RegisterModules registers several top-level modules as one set. Each module is
+// registered once however many importers reference it, and a provider name shared
+// by more than one module is registered only once (first wins), so common imports
+// don't overwrite each other. Shadowed providers are usually accidental and are
+// recorded in RegistrationWarnings.
+//
+// Registration is transactional: the whole module graph is collected and validated
+// first, and the container is only modified if every provider is valid. On error
+// the container is left exactly as it was. Eager providers are built afterwards;
+// their failures are returned together but don't undo the registration.
+func (c *Container) RegisterModules(modules ...Module) error {
+	return c.RegisterModulesWithOptions(RegisterOptions{}, modules...)
+}
+
+// RegisterModulesWithOptions is RegisterModules with control over duplicate
+// provider names, see RegisterOptions
+func (c *Container) RegisterModulesWithOptions(opts RegisterOptions, modules ...Module) error {
+	registration := &moduleRegistration{
+		visited:   make(map[any]bool),
+		providers: make(map[string]int),
+		origins:   make(map[string]string),
+		enabled:   c.ModuleEnabled,
+		strict:    opts.Strict,
+	}
+
+	for _, module := range modules {
+		if err := registration.collect(module); err != nil {
+			return err
+		}
+	}
+
+	if err := c.register(registration); err != nil {
+		return err
+	}
+	return c.resolveEager(registration.staged)
+}
+
+func (c *Container) register(registration *moduleRegistration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if registration.strict {
+		for _, provider := range registration.staged {
+			if provider.internal() {
+				continue
+			}
+			if _, exists := c.services[provider.Name]; exists && provider.Group == "" {
+				return &DuplicateProviderError{
+					Name:   provider.Name,
+					First:  "an earlier registration",
+					Second: registration.origins[provider.Name],
+				}
+			}
+		}
+	}
+	c.warnings = append(c.warnings, registration.warnings...)
+	for i := range registration.staged {
+		provider := &registration.staged[i]
+		if provider.Group != "" {
+			var service any = provider.Service
+			if provider.Factory != nil {
+				service = &lazyService{factory: provider.Factory, container: c}
+			}
+			// Named after the fact so eager members can be resolved like the rest
+			provider.Name = c.addToGroupLocked(provider.Group, service)
+			continue
+		}
+
+		owner := c
+		if provider.namespace != nil {
+			owner = c.namespaceLocked(provider.namespace)
+		}
+		if provider.internal() {
+			owner.mutex.Lock()
+			owner.setLocked(provider.Name, provider.registration(owner))
+			owner.mutex.Unlock()
+			continue
+		}
+
+		if _, exists := c.services[provider.Name]; exists {
+			c.warnings = append(c.warnings, fmt.Sprintf("provider '%s' replaces a service registered before", provider.Name))
+		}
+		c.setLocked(provider.Name, provider.registration(owner))
+	}
+	c.registerHooksLocked(registration.hooks)
+	return nil
+}
+
+// registration is what register stores for the provider. Lazy services are built
+// against owner, the module namespace for providers of a module with exports.
+func (p Provider) registration(owner *Container) any {
+	if p.Condition != nil {
+		return &lazyService{factory: p.resolveIf, container: owner}
+	} else if p.Constructor != nil {
+		return p.constructor().lazyService(p.Name, owner)
+	} else if p.Factory != nil {
+		return &lazyService{factory: p.Factory, container: owner}
+	}
+	return p.Service
+}
+
+// resolveEager builds the eager providers in registration order. Their dependencies
+// are resolved on the way, as with any Get. Every failure is reported, not only the
+// first; the providers stay registered either way.
+func (c *Container) resolveEager(providers []Provider) error {
+	var errs []error
+	for _, provider := range providers {
+		if !provider.Eager {
+			continue
+		}
+		resolver := c
+		if provider.internal() {
+			resolver = provider.namespace.container
+		}
+		if err := resolver.resolveRecovered(provider.Name); err != nil {
+			errs = append(errs, fmt.Errorf("eager provider '%s' failed: %w", provider.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveRecovered resolves name, turning a factory panic into an error
+func (c *Container) resolveRecovered(name string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if panicErr, ok := r.(error); ok {
+				err = panicErr
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	c.Get(name)
+	return nil
+}
+
+type moduleRegistration struct {
+	visited   map[any]bool
+	providers map[string]int
+	origins   map[string]string
+	staged    []Provider
+	warnings  []string
+	hooks     []stagedHooks
+	enabled   func(name string) bool
+	strict    bool
+}
+
+func moduleLabel(module Module) string {
+	if named, ok := module.(NamedModule); ok && named.GetName() != "" {
+		return fmt.Sprintf("module '%s'", named.GetName())
+	}
+	return "an unnamed module"
+}
+
+// moduleKey identifies a module instance; modules whose dynamic type isn't
+// comparable can't be tracked and are always registered.
+func moduleKey(module Module) (any, bool) {
+	if module == nil || !reflect.TypeOf(module).Comparable() {
+		return nil, false
+	}
+	return module, true
+}
+
+func (r *moduleRegistration) collect(module Module) error {
+	if module == nil {
+		return fmt.Errorf("cannot register nil module")
+	}
+
+	if key, ok := moduleKey(module); ok {
+		if r.visited[key] {
+			return nil
+		}
+		r.visited[key] = true
+	}
+
+	if named, ok := module.(NamedModule); ok && !r.enabled(named.GetName()) {
+		return nil
+	}
+
 	for _, importedModule := range module.GetImports() {
-		if err := c.RegisterModule(importedModule); err != nil {
+		if err := r.collect(importedModule); err != nil {
 			return err
 		}
 	}
 
+	namespace, err := newModuleNamespace(module)
+	if err != nil {
+		return err
+	}
+
 	for _, provider := range module.GetProviders() {
-		if provider.Factory != nil {
-			c.RegisterSingleton(provider.Name, provider.Factory)
-		} else if provider.Service != nil {
-			c.Register(provider.Name, provider.Service)
+		if err := validateProvider(provider); err != nil {
+			return err
 		}
+		if provider.Group != "" {
+			// Group members never shadow each other
+			r.staged = append(r.staged, provider)
+			continue
+		}
+		provider.namespace = namespace
+
+		// Internal providers only meet the other providers of their module
+		providers, origins := r.providers, r.origins
+		if provider.internal() {
+			providers, origins = namespace.providers, namespace.origins
+		}
+		if index, exists := providers[provider.Name]; exists {
+			// Only a conditional provider can be followed by an alternative
+			if r.staged[index].Condition != nil {
+				r.staged[index] = r.staged[index].orElse(provider)
+			} else if r.strict {
+				return &DuplicateProviderError{
+					Name:   provider.Name,
+					First:  origins[provider.Name],
+					Second: moduleLabel(module),
+				}
+			} else {
+				r.warnings = append(r.warnings, fmt.Sprintf("provider '%s' from %s is ignored: already registered by %s",
+					provider.Name, moduleLabel(module), origins[provider.Name]))
+			}
+			continue
+		}
+
+		providers[provider.Name] = len(r.staged)
+		origins[provider.Name] = moduleLabel(module)
+		r.staged = append(r.staged, provider)
 	}
 
+	r.collectHooks(module, namespace)
 	return nil
 }
+
+func validateProvider(provider Provider) error {
+	if provider.Group != "" {
+		if provider.Factory == nil && provider.Service == nil {
+			return fmt.Errorf("member of group '%s' has neither a factory nor a service", provider.Group)
+		}
+		return nil
+	}
+	if provider.Name == "" {
+		return fmt.Errorf("provider name cannot be empty")
+	}
+	if provider.Name == ContainerServiceName {
+		return fmt.Errorf("provider name '%s' is reserved for the container itself", ContainerServiceName)
+	}
+	if _, ok := parseGroupTag(provider.Name); ok {
+		return fmt.Errorf("provider name '%s' is reserved for groups", provider.Name)
+	}
+	if provider.Constructor != nil {
+		if _, err := newConstructor(provider.Constructor, provider.ParamNames); err != nil {
+			return fmt.Errorf("provider '%s': %w", provider.Name, err)
+		}
+		return nil
+	}
+	if provider.Factory == nil && provider.Service == nil {
+		return fmt.Errorf("provider '%s' has neither a factory nor a service", provider.Name)
+	}
+	return nil
+}
+
+// constructor is only called on validated providers
+func (p Provider) constructor() *constructor {
+	ctor, _ := newConstructor(p.Constructor, p.ParamNames)
+	return ctor
+}
+
+func (p Provider) resolve(c *Container) any {
+	if p.Constructor != nil {
+		return p.constructor().factory(p.Name)(c)
+	}
+	if p.Factory != nil {
+		return p.Factory(c)
+	}
+	return p.Service
+}
+
+// resolveIf resolves a conditional provider, or nil when its condition fails
+func (p Provider) resolveIf(c *Container) any {
+	if p.Condition != nil && !p.Condition(c) {
+		return nil
+	}
+	return p.resolve(c)
+}
+
+// orElse chains next behind a conditional provider. The result stays conditional
+// only while every alternative is, so an unconditional fallback closes the chain.
+func (p Provider) orElse(next Provider) Provider {
+	chained := Provider{
+		Name:      p.Name,
+		Eager:     p.Eager || next.Eager,
+		namespace: p.namespace,
+		Factory: func(c *Container) any {
+			if p.Condition(c) {
+				return p.resolve(c)
+			}
+			return next.resolveIf(c)
+		},
+	}
+	if next.Condition != nil {
+		chained.Condition = func(c *Container) bool {
+			return p.Condition(c) || next.Condition(c)
+		}
+	}
+	return chained
+}
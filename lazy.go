@@ -0,0 +1,18 @@
+package xcomp
+
+import "sync"
+
+// Lazy holds a value that is built on first use. It is safe for concurrent use:
+// init runs exactly once even when several goroutines call Get at the same time.
+// The zero value is ready to use and must not be copied after first use.
+type Lazy[T any] struct {
+	once  sync.Once
+	value T
+}
+
+func (l *Lazy[T]) Get(init func() T) T {
+	l.once.Do(func() {
+		l.value = init()
+	})
+	return l.value
+}
@@ -0,0 +1,45 @@
+package xcomp
+
+import "github.com/gofiber/fiber/v2"
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// NewLogLevelHandler reports the logger's level on GET and changes it on PUT or
+// POST with {"level": "debug"}. Mount it behind authentication: anyone able to
+// call it can flood the logs.
+func NewLogLevelHandler(l Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		controller, ok := l.(LevelController)
+		if !ok {
+			return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+				"error":   "Log level is fixed",
+				"message": "the logger does not support changing its level",
+			})
+		}
+
+		if c.Method() == fiber.MethodGet {
+			return c.JSON(fiber.Map{"level": controller.GetLevel()})
+		}
+
+		var req logLevelRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+
+		previous := controller.GetLevel()
+		if err := controller.SetLevel(req.Level); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid log level",
+				"message": err.Error(),
+			})
+		}
+
+		l.Info("Log level changed", String("from", previous), String("to", controller.GetLevel()))
+		return c.JSON(fiber.Map{"level": controller.GetLevel()})
+	}
+}
@@ -0,0 +1,108 @@
+package xcomp
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const rotatingSinkScheme = "lumberjack"
+
+var registerRotatingSink sync.Once
+
+// logRotation holds the logging.rotation.* settings
+type logRotation struct {
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+}
+
+func loadLogRotation(configService *ConfigService) (logRotation, bool) {
+	if !configService.GetBool("logging.rotation.enabled", false) {
+		return logRotation{}, false
+	}
+	return logRotation{
+		MaxSizeMB:  configService.GetInt("logging.rotation.max_size_mb", 100),
+		MaxBackups: configService.GetInt("logging.rotation.max_backups", 5),
+		MaxAgeDays: configService.GetInt("logging.rotation.max_age_days", 30),
+		Compress:   configService.GetBool("logging.rotation.compress", true),
+	}, true
+}
+
+// isLogFile reports whether a zap output path is a plain file rather than a
+// standard stream or a sink URL
+func isLogFile(path string) bool {
+	return path != "stdout" && path != "stderr" && !strings.Contains(path, "://")
+}
+
+// outputPath turns a log file path into a URL for the rotating sink, so zap keeps
+// building the logger (encoding, sampling, caller) and only the writer changes
+func (r logRotation) outputPath(path string) string {
+	registerRotatingSink.Do(func() {
+		if err := zap.RegisterSink(rotatingSinkScheme, newRotatingSink); err != nil {
+			panic("Failed to register rotating log sink: " + err.Error())
+		}
+	})
+
+	query := url.Values{}
+	query.Set("max_size_mb", strconv.Itoa(r.MaxSizeMB))
+	query.Set("max_backups", strconv.Itoa(r.MaxBackups))
+	query.Set("max_age_days", strconv.Itoa(r.MaxAgeDays))
+	query.Set("compress", strconv.FormatBool(r.Compress))
+	return (&url.URL{Scheme: rotatingSinkScheme, Opaque: path, RawQuery: query.Encode()}).String()
+}
+
+// rotatingSink adapts lumberjack to zap.Sink, which also needs Sync
+type rotatingSink struct {
+	*lumberjack.Logger
+}
+
+func (s rotatingSink) Sync() error {
+	return nil
+}
+
+func newRotatingSink(u *url.URL) (zap.Sink, error) {
+	path := u.Opaque
+	if path == "" {
+		path = u.Path
+	}
+	if path == "" {
+		return nil, fmt.Errorf("rotating log sink needs a file path")
+	}
+
+	query := u.Query()
+	number := func(key string) (int, error) {
+		value, err := strconv.Atoi(query.Get(key))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s for rotating log sink: %w", key, err)
+		}
+		return value, nil
+	}
+
+	maxSize, err := number("max_size_mb")
+	if err != nil {
+		return nil, err
+	}
+	maxBackups, err := number("max_backups")
+	if err != nil {
+		return nil, err
+	}
+	maxAge, err := number("max_age_days")
+	if err != nil {
+		return nil, err
+	}
+
+	return rotatingSink{&lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+		Compress:   query.Get("compress") == "true",
+	}}, nil
+}
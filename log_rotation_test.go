@@ -0,0 +1,44 @@
+package xcomp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRotatesFilePastMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+	cs := NewConfigService(writeConfigFile(t, `
+app:
+  environment: development
+logging:
+  output_paths: `+logFile+`
+  rotation:
+    enabled: true
+    max_size_mb: 1
+    max_backups: 2
+    compress: false
+`))
+	logger := NewLoggerWithConfig(cs)
+
+	// Well over max_size_mb in total
+	line := strings.Repeat("x", 1024)
+	for i := 0; i < 1500; i++ {
+		logger.Info(line, Int("i", i))
+	}
+	logger.Sync()
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) == 0 {
+		entries, _ := os.ReadDir(dir)
+		t.Fatalf("no rotated file next to %s after writing past max_size_mb, dir holds %v", logFile, entries)
+	}
+	if info, err := os.Stat(logFile); err != nil || info.Size() > 1<<20 {
+		t.Fatalf("current log file = %v, %v; want one under 1 MB", info, err)
+	}
+}
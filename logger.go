@@ -1,8 +1,13 @@
 package xcomp
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"runtime"
+	"sync"
+	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -18,22 +23,148 @@ type Logger interface {
 
 	With(fields ...LogField) Logger
 	WithContext(key string, value any) Logger
+	// WithError returns a logger that adds Err(err) to every entry; a nil err
+	// returns the logger unchanged
+	WithError(err error) Logger
+
+	// Sync flushes buffered entries; call it before the process exits
+	Sync() error
 
 	GetServiceName() string
 }
 
+// LogField is a key/value pair attached to a log entry. Fields built with Field carry
+// their value in Value; the typed constructors (String, Int, ...) store it unboxed so
+// hot paths avoid an allocation and the logger can skip type detection. Use
+// Interface to read the value of any field.
 type LogField struct {
 	Key   string
 	Value any
+
+	kind    fieldKind
+	integer int64
+	str     string
 }
 
+type fieldKind uint8
+
+const (
+	anyField fieldKind = iota
+	stringField
+	intField
+	boolField
+	durationField
+	errorField
+	stringerField
+)
+
 func Field(key string, value any) LogField {
 	return LogField{Key: key, Value: value}
 }
 
+func String(key, value string) LogField {
+	return LogField{Key: key, kind: stringField, str: value}
+}
+
+func Int(key string, value int) LogField {
+	return LogField{Key: key, kind: intField, integer: int64(value)}
+}
+
+func Int64(key string, value int64) LogField {
+	return LogField{Key: key, kind: intField, integer: value}
+}
+
+func Bool(key string, value bool) LogField {
+	field := LogField{Key: key, kind: boolField}
+	if value {
+		field.integer = 1
+	}
+	return field
+}
+
+func Duration(key string, value time.Duration) LogField {
+	return LogField{Key: key, kind: durationField, integer: int64(value)}
+}
+
+// StackTracer is implemented by errors that captured a stack trace; Err logs
+// it under "error_stack"
+type StackTracer interface {
+	StackTrace() string
+}
+
+// Err logs err under the conventional "error" key, with the stack of the
+// first StackTracer in its chain. A nil err writes no field.
+func Err(err error) LogField {
+	return LogField{Key: "error", Value: err, kind: errorField}
+}
+
+// errorStack returns the stack trace carried by err's chain, if any
+func errorStack(err error) string {
+	var tracer StackTracer
+	if errors.As(err, &tracer) {
+		return tracer.StackTrace()
+	}
+	return ""
+}
+
+// Stringer defers calling value.String() until the entry is actually written,
+// so disabled levels don't pay for formatting
+func Stringer(key string, value fmt.Stringer) LogField {
+	return LogField{Key: key, Value: value, kind: stringerField}
+}
+
+// Interface returns the field's value regardless of how it was constructed
+func (f LogField) Interface() any {
+	switch f.kind {
+	case stringField:
+		return f.str
+	case intField:
+		return f.integer
+	case boolField:
+		return f.integer == 1
+	case durationField:
+		return time.Duration(f.integer)
+	}
+	return f.Value
+}
+
+func (f LogField) zapField() zap.Field {
+	// Secrets logged under a telling key, e.g. Field("password", ...), are masked
+	if (f.kind == anyField || f.kind == stringField) && IsSensitiveKey(f.Key) {
+		return zap.String(f.Key, MaskedValue)
+	}
+
+	switch f.kind {
+	case stringField:
+		return zap.String(f.Key, f.str)
+	case intField:
+		return zap.Int64(f.Key, f.integer)
+	case boolField:
+		return zap.Bool(f.Key, f.integer == 1)
+	case durationField:
+		return zap.Duration(f.Key, time.Duration(f.integer))
+	case errorField:
+		err, _ := f.Value.(error)
+		return zap.NamedError(f.Key, err)
+	case stringerField:
+		if stringer, ok := f.Value.(fmt.Stringer); ok {
+			return zap.Stringer(f.Key, stringer)
+		}
+	}
+	return zap.Any(f.Key, f.Value)
+}
+
+// LevelController is implemented by loggers whose level can change at runtime
+type LevelController interface {
+	SetLevel(level string) error
+	GetLevel() string
+}
+
 type ZapLogger struct {
 	logger *zap.Logger
 	sugar  *zap.SugaredLogger
+	// level is shared with every logger derived through With
+	level zap.AtomicLevel
 }
 
 func NewLogger(configService *ConfigService) Logger {
@@ -85,6 +216,11 @@ func shouldUseColors(configService *ConfigService, format string) bool {
 		return true
 	}
 
+	// Production output is meant for log collectors, not terminals
+	if configService.IsProduction() {
+		return false
+	}
+
 	// Auto-detect terminal support
 	return isTerminal()
 }
@@ -92,8 +228,8 @@ func shouldUseColors(configService *ConfigService, format string) bool {
 func NewLoggerWithConfig(configService *ConfigService) Logger {
 	var config zap.Config
 
-	// Determine if we should use development or production config
-	isDevelopment := configService.GetBool("logging.development", false)
+	// Development or production config follows app.environment
+	isDevelopment := configService.IsDevelopment()
 	if isDevelopment {
 		config = zap.NewDevelopmentConfig()
 	} else {
@@ -136,6 +272,11 @@ func NewLoggerWithConfig(configService *ConfigService) Logger {
 		config.OutputPaths = []string{"stdout"}
 	}
 
+	// Files are rotated by size instead of growing forever when logging.rotation is enabled
+	if rotation, ok := loadLogRotation(configService); ok && isLogFile(config.OutputPaths[0]) {
+		config.OutputPaths[0] = rotation.outputPath(config.OutputPaths[0])
+	}
+
 	errorOutputPaths := configService.GetString("logging.error_output_paths", "stderr")
 	if errorOutputPaths != "" {
 		config.ErrorOutputPaths = []string{errorOutputPaths}
@@ -211,11 +352,13 @@ func NewLoggerWithConfig(configService *ConfigService) Logger {
 	return &ZapLogger{
 		logger: logger,
 		sugar:  logger.Sugar(),
+		level:  config.Level,
 	}
 }
 
 func NewDevelopmentLogger() Logger {
-	logger, err := zap.NewDevelopment()
+	config := zap.NewDevelopmentConfig()
+	logger, err := config.Build()
 	if err != nil {
 		panic("Failed to initialize development logger: " + err.Error())
 	}
@@ -223,6 +366,7 @@ func NewDevelopmentLogger() Logger {
 	return &ZapLogger{
 		logger: logger,
 		sugar:  logger.Sugar(),
+		level:  config.Level,
 	}
 }
 
@@ -231,33 +375,49 @@ func (l *ZapLogger) GetServiceName() string {
 }
 
 func (l *ZapLogger) Debug(msg string, fields ...LogField) {
-	l.logger.Debug(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.DebugLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
 func (l *ZapLogger) Info(msg string, fields ...LogField) {
-	l.logger.Info(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.InfoLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
 func (l *ZapLogger) Warn(msg string, fields ...LogField) {
-	l.logger.Warn(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.WarnLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
 func (l *ZapLogger) Error(msg string, fields ...LogField) {
-	l.logger.Error(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.ErrorLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
 func (l *ZapLogger) Fatal(msg string, fields ...LogField) {
-	l.logger.Fatal(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.FatalLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
 func (l *ZapLogger) Panic(msg string, fields ...LogField) {
-	l.logger.Panic(msg, l.convertFields(fields)...)
+	if ce := l.logger.Check(zap.PanicLevel, msg); ce != nil {
+		writeEntry(ce, fields)
+	}
 }
 
+// With returns a child logger; its sugared logger is derived from the child too,
+// so the fields apply to every call made through it
 func (l *ZapLogger) With(fields ...LogField) Logger {
+	logger := l.logger.With(l.convertFields(fields)...)
 	return &ZapLogger{
-		logger: l.logger.With(l.convertFields(fields)...),
-		sugar:  l.logger.Sugar(),
+		logger: logger,
+		sugar:  logger.Sugar(),
+		level:  l.level,
 	}
 }
 
@@ -265,10 +425,85 @@ func (l *ZapLogger) WithContext(key string, value any) Logger {
 	return l.With(Field(key, value))
 }
 
+func (l *ZapLogger) WithError(err error) Logger {
+	if err == nil {
+		return l
+	}
+	return l.With(Err(err))
+}
+
+// SetLevel changes the minimum level of this logger and every logger derived from
+// it, e.g. to debug a production issue without a restart
+func (l *ZapLogger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("unknown log level '%s'", level)
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
+func (l *ZapLogger) GetLevel() string {
+	return l.level.Level().String()
+}
+
+// Sync flushes the logger. Syncing stdout or stderr fails with EINVAL or ENOTTY
+// when they are a terminal or pipe; those errors are expected and ignored.
+func (l *ZapLogger) Sync() error {
+	err := l.logger.Sync()
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	var remaining []error
+	for _, err := range errs {
+		if !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+			remaining = append(remaining, err)
+		}
+	}
+	return errors.Join(remaining...)
+}
+
+// fieldBuffer holds the zap fields of one entry while it is written; buffers are
+// pooled so logging an entry doesn't allocate a fresh slice each time
+type fieldBuffer struct {
+	fields []zap.Field
+}
+
+var fieldBuffers = sync.Pool{
+	New: func() any { return &fieldBuffer{fields: make([]zap.Field, 0, 8)} },
+}
+
+// writeEntry converts fields into a pooled buffer and writes ce. Entries below
+// the level never get here, so disabled levels skip the conversion entirely.
+func writeEntry(ce *zapcore.CheckedEntry, fields []LogField) {
+	buf := fieldBuffers.Get().(*fieldBuffer)
+	buf.fields = appendZapFields(buf.fields[:0], fields)
+	ce.Write(buf.fields...)
+	clear(buf.fields)
+	fieldBuffers.Put(buf)
+}
+
 func (l *ZapLogger) convertFields(fields []LogField) []zap.Field {
-	zapFields := make([]zap.Field, len(fields))
-	for i, field := range fields {
-		zapFields[i] = zap.Any(field.Key, field.Value)
+	return appendZapFields(make([]zap.Field, 0, len(fields)), fields)
+}
+
+func appendZapFields(zapFields []zap.Field, fields []LogField) []zap.Field {
+	for _, field := range fields {
+		zapFields = append(zapFields, field.zapField())
+		if field.kind != errorField {
+			continue
+		}
+		if err, ok := field.Value.(error); ok && err != nil {
+			if stack := errorStack(err); stack != "" {
+				zapFields = append(zapFields, zap.String(field.Key+"_stack", stack))
+			}
+		}
 	}
 	return zapFields
 }
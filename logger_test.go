@@ -0,0 +1,151 @@
+package xcomp
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// benchID stands in for the uuid.UUID ids the order service logs
+type benchID [16]byte
+
+func (id benchID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func newBenchLogger() *ZapLogger {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		level,
+	)
+	logger := zap.New(core)
+	return &ZapLogger{logger: logger, sugar: logger.Sugar(), level: level}
+}
+
+// BenchmarkOrderServiceInfo mirrors the "Creating order" and "Getting orders
+// for customer" entries, built with Field as they used to be and with the
+// typed constructors they use now. Allocations left over come from boxing the
+// ids into fmt.Stringer and from formatting them.
+func BenchmarkOrderServiceInfo(b *testing.B) {
+	logger := newBenchLogger()
+	customerID := benchID{1, 2, 3, 4}
+	items := make([]int, 3)
+	page, pageSize := int32(2), int32(20)
+
+	b.Run("Field", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info("Creating order",
+				Field("customer_id", customerID),
+				Field("items_count", len(items)))
+			logger.Info("Getting orders for customer",
+				Field("customer_id", customerID),
+				Field("page", page),
+				Field("page_size", pageSize))
+		}
+	})
+
+	b.Run("Typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info("Creating order",
+				Stringer("customer_id", customerID),
+				Int("items_count", len(items)))
+			logger.Info("Getting orders for customer",
+				Stringer("customer_id", customerID),
+				Int64("page", int64(page)),
+				Int64("page_size", int64(pageSize)))
+		}
+	})
+
+	b.Run("TypedDisabled", func(b *testing.B) {
+		logger := newBenchLogger()
+		if err := logger.SetLevel("warn"); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info("Creating order",
+				Stringer("customer_id", customerID),
+				Int("items_count", len(items)))
+		}
+	})
+}
+
+func newObservedLogger() (*ZapLogger, *observer.ObservedLogs) {
+	level := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+	return &ZapLogger{logger: logger, sugar: logger.Sugar(), level: level}, logs
+}
+
+func assertContext(t *testing.T, entry observer.LoggedEntry, key string, want any) {
+	t.Helper()
+	got, ok := entry.ContextMap()[key]
+	if !ok {
+		t.Fatalf("entry %q has no %q field; fields: %v", entry.Message, key, entry.ContextMap())
+	}
+	if got != want {
+		t.Fatalf("entry %q field %q = %v, want %v", entry.Message, key, got, want)
+	}
+}
+
+func TestWithAddsFieldsToSugaredCalls(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	child := logger.With(String("request_id", "r-1")).(*ZapLogger)
+	child.sugar.Infow("sugared", "step", 1)
+	child.sugar.Infof("sugared %s", "formatted")
+	child.Info("structured")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	for _, entry := range entries {
+		assertContext(t, entry, "request_id", "r-1")
+	}
+	assertContext(t, entries[0], "step", int64(1))
+}
+
+func TestWithContextAndWithErrorAddFields(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	child := logger.WithContext("tenant", "acme").WithError(errors.New("boom")).(*ZapLogger)
+	child.sugar.Warnw("sugared")
+	child.Warn("structured")
+
+	for _, entry := range logs.AllUntimed() {
+		assertContext(t, entry, "tenant", "acme")
+		assertContext(t, entry, "error", "boom")
+	}
+	if tagged := logs.FilterField(zap.String("tenant", "acme")).Len(); tagged != 2 {
+		t.Fatalf("%d entries carry the tenant field, want 2", tagged)
+	}
+}
+
+func TestWithLeavesParentUnchanged(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	logger.With(String("request_id", "r-1"))
+	logger.sugar.Infow("parent sugared")
+	logger.Info("parent structured", Int("attempt", 2))
+	logger.Info("parent again")
+
+	for _, entry := range logs.AllUntimed() {
+		if _, ok := entry.ContextMap()["request_id"]; ok {
+			t.Fatalf("parent entry %q picked up the child's field", entry.Message)
+		}
+	}
+	// Entries are written through pooled field buffers; none may leak into the next
+	if fields := logs.AllUntimed()[2].ContextMap(); len(fields) != 0 {
+		t.Fatalf("entry without fields logged %v", fields)
+	}
+}
@@ -0,0 +1,169 @@
+package xcomp
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUCacheOptions bounds an in-process cache by entry count and age.
+// Hooks are optional and are called outside the cache lock.
+type LRUCacheOptions struct {
+	MaxEntries int
+	TTL        time.Duration
+	OnHit      func(key any)
+	OnMiss     func(key any)
+	OnEvict    func(key any)
+}
+
+// LRUCache is an in-process, size-bounded cache with per-entry expiry.
+// It is meant as a first tier in front of Redis for small, hot data.
+type LRUCache[K comparable, V any] struct {
+	opts    LRUCacheOptions
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+func NewLRUCache[K comparable, V any](opts LRUCacheOptions) *LRUCache[K, V] {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 1000
+	}
+
+	return &LRUCache[K, V]{
+		opts:    opts,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *LRUCache[K, V]) GetServiceName() string {
+	return "LRUCache"
+}
+
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	value, found, expired := c.lookup(key)
+	c.mu.Unlock()
+
+	if expired {
+		c.notify(c.opts.OnEvict, key)
+	}
+	if found {
+		c.notify(c.opts.OnHit, key)
+	} else {
+		c.notify(c.opts.OnMiss, key)
+	}
+	return value, found
+}
+
+func (c *LRUCache[K, V]) lookup(key K) (value V, found bool, expired bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return value, false, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V])
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return value, false, true
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, false
+}
+
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	var expiresAt time.Time
+	if c.opts.TTL > 0 {
+		expiresAt = time.Now().Add(c.opts.TTL)
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	var evicted []K
+	for c.order.Len() > c.opts.MaxEntries {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*lruEntry[K, V])
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		evicted = append(evicted, entry.key)
+	}
+	c.mu.Unlock()
+
+	for _, evictedKey := range evicted {
+		c.notify(c.opts.OnEvict, evictedKey)
+	}
+}
+
+// GetOrLoad returns the cached value or calls loader and caches its result.
+// Loader errors are returned as-is and nothing is cached.
+func (c *LRUCache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	value, err := loader()
+	if err != nil {
+		return value, err
+	}
+
+	c.Set(key, value)
+	return value, nil
+}
+
+func (c *LRUCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+func (c *LRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache[K, V]) notify(hook func(key any), key K) {
+	if hook != nil {
+		hook(key)
+	}
+}
+
+// NewLRUCacheModule provides a string-keyed LRUCache under name
+func NewLRUCacheModule[V any](name string, opts LRUCacheOptions) Module {
+	return NewModule().
+		AddFactory(name, func(c *Container) any {
+			return NewLRUCache[string, V](opts)
+		}).
+		Build()
+}
@@ -0,0 +1,151 @@
+package xcomp
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a monotonically increasing value safe for concurrent use
+type Counter struct {
+	value atomic.Int64
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(delta int64) {
+	c.value.Add(delta)
+}
+
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+// Metrics is a registry of named, optionally labeled counters.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	mu       sync.RWMutex
+	counters map[string]*Counter
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		counters: make(map[string]*Counter),
+	}
+}
+
+func (m *Metrics) GetServiceName() string {
+	return "Metrics"
+}
+
+// Counter returns the counter for name and label pairs, creating it on first use.
+// Labels are given as key, value pairs: m.Counter("cache_hits_total", "entity", "product").
+func (m *Metrics) Counter(name string, labels ...string) *Counter {
+	if m == nil {
+		return &Counter{}
+	}
+
+	key := metricKey(name, labels)
+
+	m.mu.RLock()
+	counter, ok := m.counters[key]
+	m.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if counter, ok = m.counters[key]; !ok {
+		counter = &Counter{}
+		m.counters[key] = counter
+	}
+	return counter
+}
+
+// Snapshot returns the current value of every counter keyed by its exposition name
+func (m *Metrics) Snapshot() map[string]int64 {
+	snapshot := make(map[string]int64)
+	if m == nil {
+		return snapshot
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for key, counter := range m.counters {
+		snapshot[key] = counter.Value()
+	}
+	return snapshot
+}
+
+// ServeHTTP writes counters in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := m.Snapshot()
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s %d\n", key, snapshot[key])
+	}
+}
+
+func metricKey(name string, labels []string) string {
+	if len(labels) < 2 {
+		return name
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CacheMetrics counts cache hits and misses per entity type
+type CacheMetrics struct {
+	metrics *Metrics
+}
+
+func NewCacheMetrics(metrics *Metrics) *CacheMetrics {
+	return &CacheMetrics{metrics: metrics}
+}
+
+func (cm *CacheMetrics) Hit(entity string) {
+	cm.metrics.Counter("cache_hits_total", "entity", entity).Inc()
+}
+
+func (cm *CacheMetrics) Miss(entity string) {
+	cm.metrics.Counter("cache_misses_total", "entity", entity).Inc()
+}
+
+// HitRatio returns hits / (hits + misses) for entity, or 0 before any lookups
+func (cm *CacheMetrics) HitRatio(entity string) float64 {
+	hits := cm.metrics.Counter("cache_hits_total", "entity", entity).Value()
+	misses := cm.metrics.Counter("cache_misses_total", "entity", entity).Value()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// NewMetricsModule provides a shared Metrics registry as "Metrics" and CacheMetrics as "CacheMetrics"
+func NewMetricsModule() Module {
+	return NewModule().
+		AddFactory("Metrics", func(c *Container) any {
+			return NewMetrics()
+		}).
+		AddFactory("CacheMetrics", func(c *Container) any {
+			metrics, _ := c.Get("Metrics").(*Metrics)
+			return NewCacheMetrics(metrics)
+		}).
+		Build()
+}
@@ -0,0 +1,88 @@
+package xcomp
+
+import "fmt"
+
+// ExportingModule is a module that keeps its providers to itself except the
+// exported ones, see ModuleBuilder.Export
+type ExportingModule interface {
+	Module
+	// GetExports returns the provider names visible to importers; nil exports all
+	GetExports() []string
+}
+
+// Export limits the providers importers can resolve to names. The others, such as
+// a repository behind an exported service, stay resolvable from the module's own
+// factories only. Export with no names hides every provider except group members,
+// which always join the container-wide group. Imported modules are unaffected.
+func (mb *ModuleBuilder) Export(names ...string) *ModuleBuilder {
+	if mb.exports == nil {
+		mb.exports = make([]string, 0, len(names))
+	}
+	mb.exports = append(mb.exports, names...)
+	return mb
+}
+
+// moduleNamespace holds the providers of one module with exports. Its container is
+// a scope of the container the module is registered in, so the module's factories
+// see its internal providers as well as everything registered outside it.
+type moduleNamespace struct {
+	label     string
+	exports   map[string]bool
+	providers map[string]int
+	origins   map[string]string
+	container *Container
+}
+
+// newModuleNamespace returns nil for modules exporting every provider
+func newModuleNamespace(module Module) (*moduleNamespace, error) {
+	exporting, ok := module.(ExportingModule)
+	if !ok || exporting.GetExports() == nil {
+		return nil, nil
+	}
+
+	provided := make(map[string]bool)
+	for _, provider := range module.GetProviders() {
+		provided[provider.Name] = true
+	}
+
+	namespace := &moduleNamespace{
+		label:     moduleLabel(module),
+		exports:   make(map[string]bool),
+		providers: make(map[string]int),
+		origins:   make(map[string]string),
+	}
+	for _, name := range exporting.GetExports() {
+		if !provided[name] {
+			return nil, fmt.Errorf("%s exports '%s' but does not provide it", namespace.label, name)
+		}
+		namespace.exports[name] = true
+	}
+	return namespace, nil
+}
+
+// internal reports whether the provider is hidden in its module's namespace
+func (p Provider) internal() bool {
+	return p.namespace != nil && p.Group == "" && !p.namespace.exports[p.Name]
+}
+
+// namespaceLocked creates the namespace container on first use and records it in
+// the registration order, so Shutdown closes the module's internal services where
+// it would have closed them had they been registered here; the caller holds the
+// write lock
+func (c *Container) namespaceLocked(namespace *moduleNamespace) *Container {
+	if namespace.container != nil {
+		return namespace.container
+	}
+	namespace.container = c.newScopeLocked()
+
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]*Container)
+	}
+	key := namespace.label
+	for i := 2; c.namespaces[key] != nil; i++ {
+		key = fmt.Sprintf("%s (%d)", namespace.label, i)
+	}
+	c.namespaces[key] = namespace.container
+	c.order = append(c.order, key)
+	return namespace.container
+}
@@ -0,0 +1,58 @@
+package xcomp
+
+import (
+	"slices"
+	"testing"
+)
+
+type exportsRepository struct{}
+
+type exportsService struct {
+	repository *exportsRepository
+}
+
+func newExportingModule() Module {
+	return NewModule().
+		Named("orders").
+		AddService("OrderRepository", &exportsRepository{}).
+		AddFactory("OrderService", func(c *Container) any {
+			repository, _ := c.Get("OrderRepository").(*exportsRepository)
+			return &exportsService{repository: repository}
+		}).
+		Export("OrderService").
+		Build()
+}
+
+func TestModuleExportHidesInternalProviders(t *testing.T) {
+	c := NewContainer()
+	if err := c.RegisterModule(newExportingModule()); err != nil {
+		t.Fatal(err)
+	}
+
+	if repository := c.Get("OrderRepository"); repository != nil {
+		t.Fatalf("root container resolved the internal OrderRepository: %v", repository)
+	}
+	if slices.Contains(c.ListServices(), "OrderRepository") {
+		t.Fatal("ListServices includes the internal OrderRepository")
+	}
+
+	service, ok := c.Get("OrderService").(*exportsService)
+	if !ok {
+		t.Fatal("root container did not resolve the exported OrderService")
+	}
+	if service.repository == nil {
+		t.Fatal("OrderService's factory could not resolve its module's OrderRepository")
+	}
+}
+
+func TestModuleExportRequiresProvidedName(t *testing.T) {
+	module := NewModule().
+		Named("orders").
+		AddService("OrderRepository", &exportsRepository{}).
+		Export("OrderService").
+		Build()
+
+	if err := NewContainer().RegisterModule(module); err == nil {
+		t.Fatal("RegisterModule accepted an export the module does not provide")
+	}
+}
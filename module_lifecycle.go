@@ -0,0 +1,163 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ModuleHook runs when the modules start or stop. It receives the container the
+// module's providers resolve from, its namespace for a module with exports.
+type ModuleHook func(ctx context.Context, c *Container) error
+
+// LifecycleModule is a module with start and stop hooks, see ModuleBuilder.OnStart
+type LifecycleModule interface {
+	Module
+	GetOnStart() []ModuleHook
+	GetOnStop() []ModuleHook
+}
+
+// OnStart adds a hook run by Container.StartModules, e.g. to start a job server.
+// Long-running work belongs in a goroutine bound to ctx; the hook should return
+// once the work is started.
+func (mb *ModuleBuilder) OnStart(hook ModuleHook) *ModuleBuilder {
+	mb.onStart = append(mb.onStart, hook)
+	return mb
+}
+
+// OnStop adds a hook run by Container.StopModules
+func (mb *ModuleBuilder) OnStop(hook ModuleHook) *ModuleBuilder {
+	mb.onStop = append(mb.onStop, hook)
+	return mb
+}
+
+func (bm *BasicModule) GetOnStart() []ModuleHook {
+	return bm.onStart
+}
+
+func (bm *BasicModule) GetOnStop() []ModuleHook {
+	return bm.onStop
+}
+
+// moduleLifecycle is the hooks of one registered module
+type moduleLifecycle struct {
+	label     string
+	container *Container
+	onStart   []ModuleHook
+	onStop    []ModuleHook
+	started   bool
+}
+
+// stagedHooks are the hooks collected with a module's providers
+type stagedHooks struct {
+	label     string
+	namespace *moduleNamespace
+	onStart   []ModuleHook
+	onStop    []ModuleHook
+}
+
+type moduleLifecycles struct {
+	modules []*moduleLifecycle
+	// mutex serializes StartModules and StopModules
+	mutex sync.Mutex
+}
+
+func (r *moduleRegistration) collectHooks(module Module, namespace *moduleNamespace) {
+	lifecycle, ok := module.(LifecycleModule)
+	if !ok || (len(lifecycle.GetOnStart()) == 0 && len(lifecycle.GetOnStop()) == 0) {
+		return
+	}
+	r.hooks = append(r.hooks, stagedHooks{
+		label:     moduleLabel(module),
+		namespace: namespace,
+		onStart:   lifecycle.GetOnStart(),
+		onStop:    lifecycle.GetOnStop(),
+	})
+}
+
+// registerHooksLocked records the staged hooks; the caller holds the write lock
+func (c *Container) registerHooksLocked(hooks []stagedHooks) {
+	for _, staged := range hooks {
+		container := c
+		if staged.namespace != nil {
+			container = c.namespaceLocked(staged.namespace)
+		}
+		c.lifecycle.modules = append(c.lifecycle.modules, &moduleLifecycle{
+			label:     staged.label,
+			container: container,
+			onStart:   staged.onStart,
+			onStop:    staged.onStop,
+		})
+	}
+}
+
+func (c *Container) lifecycleModules() []*moduleLifecycle {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]*moduleLifecycle(nil), c.lifecycle.modules...)
+}
+
+// StartModules runs the OnStart hooks of the registered modules in dependency
+// order, imports before their importers. A module whose hook fails is not marked
+// started, and the other modules still start; the failures are returned joined.
+// Modules already started are skipped, so StartModules can be called again after
+// registering more modules.
+func (c *Container) StartModules(ctx context.Context) error {
+	c.lifecycle.mutex.Lock()
+	defer c.lifecycle.mutex.Unlock()
+
+	var errs []error
+	for _, module := range c.lifecycleModules() {
+		if module.started {
+			continue
+		}
+		if err := module.start(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s failed to start: %w", module.label, err))
+			continue
+		}
+		module.started = true
+	}
+	return errors.Join(errs...)
+}
+
+// StopModules runs the OnStop hooks of the started modules in reverse start order
+// and returns the failures joined. Every started module is stopped, whatever the
+// others return.
+func (c *Container) StopModules(ctx context.Context) error {
+	c.lifecycle.mutex.Lock()
+	defer c.lifecycle.mutex.Unlock()
+
+	var errs []error
+	modules := c.lifecycleModules()
+	for i := len(modules) - 1; i >= 0; i-- {
+		module := modules[i]
+		if !module.started {
+			continue
+		}
+		module.started = false
+		if err := module.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s failed to stop: %w", module.label, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *moduleLifecycle) start(ctx context.Context) error {
+	for _, hook := range m.onStart {
+		if err := hook(ctx, m.container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *moduleLifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(m.onStop) - 1; i >= 0; i-- {
+		if err := m.onStop[i](ctx, m.container); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package xcomp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Money is a currency amount that always serializes with exactly two decimals,
+// so 19.99 goes over the wire as 19.99 and never as 19.989999999999998
+type Money float64
+
+type MoneyFormat int32
+
+const (
+	// MoneyAsNumber encodes amounts as JSON numbers: 19.99
+	MoneyAsNumber MoneyFormat = iota
+	// MoneyAsString encodes amounts as JSON strings: "19.99"
+	MoneyAsString
+)
+
+var moneyFormat atomic.Int32
+
+// SetMoneyFormat selects how Money is encoded process-wide. Decoding accepts both forms.
+func SetMoneyFormat(format MoneyFormat) {
+	moneyFormat.Store(int32(format))
+}
+
+// ParseMoneyFormat maps "string" to MoneyAsString; anything else is MoneyAsNumber
+func ParseMoneyFormat(value string) MoneyFormat {
+	if strings.EqualFold(strings.TrimSpace(value), "string") {
+		return MoneyAsString
+	}
+	return MoneyAsNumber
+}
+
+// Round returns the amount rounded half away from zero to cents
+func (m Money) Round() Money {
+	return Money(math.Round(float64(m)*100) / 100)
+}
+
+func (m Money) Float64() float64 {
+	return float64(m.Round())
+}
+
+func (m Money) String() string {
+	return strconv.FormatFloat(float64(m.Round()), 'f', 2, 64)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if MoneyFormat(moneyFormat.Load()) == MoneyAsString {
+		return []byte(`"` + m.String() + `"`), nil
+	}
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("invalid money value: %w", err)
+		}
+		data = []byte(strings.TrimSpace(text))
+	}
+
+	value, err := strconv.ParseFloat(string(data), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("invalid money value: %s", data)
+	}
+
+	*m = Money(value).Round()
+	return nil
+}
@@ -0,0 +1,60 @@
+package xcomp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OpError records which operation failed on which entity, so the error can be logged
+// with structured context far from where it happened
+type OpError struct {
+	Op     string
+	Entity string
+	ID     any
+	Err    error
+}
+
+func (e *OpError) Error() string {
+	if e.ID != nil {
+		return fmt.Sprintf("%s %s %v: %v", e.Op, e.Entity, e.ID, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Entity, e.Err)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// WrapOp attaches operation context to err, e.g. WrapOp("ProductRepository.GetByID",
+// "product", id, err). It returns nil for a nil err; id may be nil for list operations.
+func WrapOp(op, entity string, id any, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &OpError{Op: op, Entity: entity, ID: id, Err: err}
+}
+
+// ErrorFields returns log fields for err, including the op, entity and id of the
+// innermost OpError in its chain, which is closest to where the failure happened
+func ErrorFields(err error) []LogField {
+	if err == nil {
+		return nil
+	}
+
+	fields := []LogField{Err(err)}
+
+	var innermost *OpError
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if opErr, ok := current.(*OpError); ok {
+			innermost = opErr
+		}
+	}
+	if innermost != nil {
+		fields = append(fields, Field("op", innermost.Op), Field("entity", innermost.Entity))
+		if innermost.ID != nil {
+			fields = append(fields, Field("entity_id", fmt.Sprint(innermost.ID)))
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,34 @@
+package xcomp
+
+// Ptr returns a pointer to v, for filling optional DTO fields from literals
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns *p, or def when p is nil
+func Deref[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// ApplyIfSet copies *src into *dst when src is set, the partial update pattern for
+// optional request fields:
+//
+//	xcomp.ApplyIfSet(&order.Status, req.Status)
+func ApplyIfSet[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// ApplyIfSetFunc is ApplyIfSet for fields whose request and entity types differ,
+// converting *src before assigning it:
+//
+//	xcomp.ApplyIfSetFunc(&order.TaxAmount, req.TaxAmount, xcomp.Money.Float64)
+func ApplyIfSetFunc[S, D any](dst *D, src *S, convert func(S) D) {
+	if src != nil {
+		*dst = convert(*src)
+	}
+}
@@ -0,0 +1,33 @@
+package xcomp
+
+import "fmt"
+
+// Override replaces the registration under name with service, discarding any
+// instance already built from the previous registration. It is meant for tests,
+// e.g. swapping the OrderRepository of a registered module for a mock, and fails
+// when nothing was registered under name. Services that already resolved the
+// old registration keep it, so override before resolving its dependents.
+func (c *Container) Override(name string, service any) error {
+	return c.override(name, service)
+}
+
+// OverrideFactory is Override with a lazy factory, built on the next Get
+func (c *Container) OverrideFactory(name string, factory func(*Container) any) error {
+	if factory == nil {
+		return fmt.Errorf("cannot override service '%s' with a nil factory", name)
+	}
+	return c.override(name, &lazyService{factory: factory, container: c})
+}
+
+func (c *Container) override(name string, service any) error {
+	if _, exists := c.lookup(name); !exists {
+		return fmt.Errorf("cannot override service '%s': it was never registered", name)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// A fresh registration also means a fresh sync.Once for lazy services; in a
+	// scope, an inherited service is overridden for the scope only
+	c.setLocked(name, service)
+	return nil
+}
@@ -0,0 +1,67 @@
+package xcomp
+
+const (
+	// DefaultPageSize replaces a missing or non-positive page size
+	DefaultPageSize int32 = 10
+	// MaxPageSize caps the page size a client can request
+	MaxPageSize int32 = 100
+)
+
+// Page is one page of a listing, the response shape shared by list endpoints
+type Page[T any] struct {
+	Items      []T   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int32 `json:"page"`
+	PageSize   int32 `json:"page_size"`
+	TotalPages int32 `json:"total_pages"`
+}
+
+// NewPage builds a page of items out of total, clamping page and pageSize as
+// ClampPage does. Items is never nil, so an empty page encodes as [].
+func NewPage[T any](items []T, total int64, page, pageSize int32) Page[T] {
+	page, pageSize = ClampPage(page, pageSize)
+	if items == nil {
+		items = []T{}
+	}
+	if total < 0 {
+		total = 0
+	}
+	return Page[T]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int32((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+}
+
+// MapPage builds a page from items converted one by one, e.g. entities to DTOs
+func MapPage[S, T any](items []S, total int64, page, pageSize int32, convert func(S) T) Page[T] {
+	converted := make([]T, len(items))
+	for i, item := range items {
+		converted[i] = convert(item)
+	}
+	return NewPage(converted, total, page, pageSize)
+}
+
+// ClampPage normalizes pagination input: page is at least 1, a page size below 1
+// becomes DefaultPageSize and one above MaxPageSize is capped to it
+func ClampPage(page, pageSize int32) (int32, int32) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
+
+// PageOffset clamps page and pageSize and returns them with the offset of the
+// page's first row, ready for a LIMIT/OFFSET query
+func PageOffset(page, pageSize int32) (int32, int32, int32) {
+	page, pageSize = ClampPage(page, pageSize)
+	return page, pageSize, (page - 1) * pageSize
+}
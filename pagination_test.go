@@ -0,0 +1,45 @@
+package xcomp
+
+import "testing"
+
+func TestNewPageClampsPageAndSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		page, size   int32
+		wantPage     int32
+		wantSize     int32
+		wantNumPages int32
+	}{
+		{name: "in range", page: 2, size: 20, wantPage: 2, wantSize: 20, wantNumPages: 3},
+		{name: "page zero", page: 0, size: 20, wantPage: 1, wantSize: 20, wantNumPages: 3},
+		{name: "negative page", page: -3, size: 20, wantPage: 1, wantSize: 20, wantNumPages: 3},
+		{name: "size zero", page: 1, size: 0, wantPage: 1, wantSize: DefaultPageSize, wantNumPages: 5},
+		{name: "negative size", page: 1, size: -5, wantPage: 1, wantSize: DefaultPageSize, wantNumPages: 5},
+		{name: "size above max", page: 1, size: 500, wantPage: 1, wantSize: MaxPageSize, wantNumPages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPage([]int{1}, 45, tt.page, tt.size)
+			if got.Page != tt.wantPage || got.PageSize != tt.wantSize || got.TotalPages != tt.wantNumPages {
+				t.Fatalf("NewPage(page %d, size %d) = page %d, size %d, %d pages; want page %d, size %d, %d pages",
+					tt.page, tt.size, got.Page, got.PageSize, got.TotalPages, tt.wantPage, tt.wantSize, tt.wantNumPages)
+			}
+		})
+	}
+}
+
+func TestNewPageWithoutItems(t *testing.T) {
+	got := NewPage[int](nil, 0, 1, 10)
+	if got.Items == nil || got.TotalPages != 0 {
+		t.Fatalf("NewPage(nil, 0) = %+v, want empty non-nil items and 0 pages", got)
+	}
+}
+
+func TestPageOffset(t *testing.T) {
+	if page, size, offset := PageOffset(3, 25); page != 3 || size != 25 || offset != 50 {
+		t.Fatalf("PageOffset(3, 25) = %d, %d, %d; want 3, 25, 50", page, size, offset)
+	}
+	if page, size, offset := PageOffset(0, 1000); page != 1 || size != MaxPageSize || offset != 0 {
+		t.Fatalf("PageOffset(0, 1000) = %d, %d, %d; want 1, %d, 0", page, size, offset, MaxPageSize)
+	}
+}
@@ -0,0 +1,56 @@
+package xcomp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by the request ID middleware, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestIDField is a log field carrying ctx's request ID, for correlating logs across layers
+func RequestIDField(ctx context.Context) LogField {
+	return Field("request_id", RequestIDFromContext(ctx))
+}
+
+// NewRequestIDMiddleware reuses the incoming X-Request-ID or generates one, echoes it
+// in the response and stores it in the user context. Handlers must pass c.UserContext()
+// down to services for the ID to reach repositories and their logs.
+func NewRequestIDMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDHeader, requestID)
+		c.Locals("request_id", requestID)
+		c.SetUserContext(ContextWithRequestID(c.UserContext(), requestID))
+		return c.Next()
+	}
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,164 @@
+package xcomp
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// CircularDependencyError is the panic value of a Get that would wait on its own
+// construction, e.g. A's factory resolving B while B's factory resolves A
+type CircularDependencyError struct {
+	Chain []string
+}
+
+func (e *CircularDependencyError) Error() string {
+	return "circular dependency detected: " + strings.Join(e.Chain, " -> ")
+}
+
+// DefaultMaxResolutionDepth bounds how many lazy services may be under construction
+// on one goroutine at once, far beyond any sane dependency graph
+const DefaultMaxResolutionDepth = 100
+
+// ResolutionDepthError is the panic value of a Get nested deeper than the container's
+// maximum resolution depth, see Container.SetMaxResolutionDepth
+type ResolutionDepthError struct {
+	MaxDepth int
+	Chain    []string
+}
+
+func (e *ResolutionDepthError) Error() string {
+	return fmt.Sprintf("maximum resolution depth of %d exceeded: %s", e.MaxDepth, strings.Join(e.Chain, " -> "))
+}
+
+// resolutionTracker keeps the chain of lazy services each goroutine is constructing.
+// Without it a cycle re-enters sync.Once on the same goroutine and deadlocks silently.
+// Only first resolutions are tracked, so resolved services cost nothing.
+type resolutionTracker struct {
+	mu       sync.Mutex
+	chains   map[uint64][]string
+	maxDepth int
+	// active mirrors len(chains) so lookups outside any construction skip the lock
+	active atomic.Int32
+}
+
+func (t *resolutionTracker) limit() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limitLocked()
+}
+
+func (t *resolutionTracker) limitLocked() int {
+	if t.maxDepth <= 0 {
+		return DefaultMaxResolutionDepth
+	}
+	return t.maxDepth
+}
+
+func (t *resolutionTracker) setLimit(depth int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxDepth = depth
+}
+
+// enter records that the calling goroutine is constructing name and returns the
+// func that removes it again. A name already in the goroutine's chain panics with
+// the full chain; a shared dependency reached twice, one after the other, does not.
+// So does a chain growing past the maximum depth, before the stack runs out.
+func (t *resolutionTracker) enter(name string) func() {
+	goroutine, err := goroutineID()
+	if err != nil {
+		// Constructing untracked only loses cycle detection and dependency edges
+		return func() {}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chain := t.chains[goroutine]
+	for i, resolving := range chain {
+		if resolving == name {
+			cycle := append(append([]string(nil), chain[i:]...), name)
+			panic(&CircularDependencyError{Chain: cycle})
+		}
+	}
+
+	if maxDepth := t.limitLocked(); len(chain) >= maxDepth {
+		panic(&ResolutionDepthError{MaxDepth: maxDepth, Chain: append(append([]string(nil), chain...), name)})
+	}
+
+	if t.chains == nil {
+		t.chains = make(map[uint64][]string)
+	}
+	if len(chain) == 0 {
+		t.active.Add(1)
+	}
+	t.chains[goroutine] = append(chain, name)
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		chain := t.chains[goroutine]
+		if len(chain) <= 1 {
+			delete(t.chains, goroutine)
+			t.active.Add(-1)
+			return
+		}
+		t.chains[goroutine] = chain[:len(chain)-1]
+	}
+}
+
+// current returns the service the calling goroutine is constructing. Outside any
+// construction it returns without locking or looking up the goroutine.
+func (t *resolutionTracker) current() (string, bool) {
+	if t.active.Load() == 0 {
+		return "", false
+	}
+
+	goroutine, err := goroutineID()
+	if err != nil {
+		return "", false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	chain := t.chains[goroutine]
+	if len(chain) == 0 {
+		return "", false
+	}
+	return chain[len(chain)-1], true
+}
+
+// goroutineID parses the id from the "goroutine N [running]:" stack header.
+//
+// Factories only receive the *Container, so a Get cannot say which construction
+// it belongs to; the goroutine running it is what ties it to a chain. Keying the
+// chains by goroutine keeps concurrent first resolutions of the same services
+// from mistaking each other for a cycle. Go has no goroutine-local storage, so
+// the id is read from the stack header, whose format the runtime does not
+// promise; if it cannot be parsed the error is returned and resolution goes on
+// untracked.
+func goroutineID() (uint64, error) {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if end := bytes.IndexByte(header, ' '); end >= 0 {
+		header = header[:end]
+	}
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("xcomp: cannot parse goroutine id from %q: %w", header, err)
+	}
+	return id, nil
+}
+
+// SetMaxResolutionDepth limits how deeply lazy services may resolve one another
+// while being constructed; exceeding it panics with *ResolutionDepthError, which
+// RegisterModules reports as an error for eager providers. A depth of 0 or less
+// restores DefaultMaxResolutionDepth. Scopes created afterwards inherit the limit.
+func (c *Container) SetMaxResolutionDepth(depth int) {
+	c.resolution.setLimit(depth)
+}
@@ -0,0 +1,77 @@
+package xcomp
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGoroutineIDDiffersPerGoroutine(t *testing.T) {
+	own, err := goroutineID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var other uint64
+	var otherErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		other, otherErr = goroutineID()
+	}()
+	<-done
+
+	if otherErr != nil {
+		t.Fatal(otherErr)
+	}
+	if own == other {
+		t.Fatalf("two goroutines share id %d", own)
+	}
+}
+
+func TestGetPanicsOnCircularDependency(t *testing.T) {
+	c := NewContainer()
+	c.RegisterSingleton("A", func(c *Container) any { return c.Get("B") })
+	c.RegisterSingleton("B", func(c *Container) any { return c.Get("A") })
+
+	defer func() {
+		err, _ := recover().(error)
+		var cycle *CircularDependencyError
+		if !errors.As(err, &cycle) {
+			t.Fatalf("Get panicked with %v, want a *CircularDependencyError", err)
+		}
+		if want := []string{"A", "B", "A"}; !reflect.DeepEqual(cycle.Chain, want) {
+			t.Fatalf("cycle = %v, want %v", cycle.Chain, want)
+		}
+	}()
+	c.Get("A")
+}
+
+// Chains are kept per goroutine, so concurrent first resolutions of the same
+// services must not be reported as a cycle
+func TestConcurrentResolutionIsNotACycle(t *testing.T) {
+	c := NewContainer()
+	c.RegisterSingleton("Shared", func(c *Container) any {
+		time.Sleep(10 * time.Millisecond)
+		return struct{}{}
+	})
+	c.RegisterSingleton("A", func(c *Container) any { return c.Get("Shared") })
+	c.RegisterSingleton("B", func(c *Container) any { return c.Get("Shared") })
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"A", "B"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("Get(%q) panicked: %v", name, r)
+				}
+			}()
+			c.Get(name)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,45 @@
+package xcomp
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoutesGroup is the group MountRoutes collects route registrars from
+const RoutesGroup = "routes"
+
+// RouteMount is a RouteRegistrar mounted under Prefix, see ModuleBuilder.AddRoutes
+type RouteMount struct {
+	Prefix    string
+	Registrar RouteRegistrar
+}
+
+// AddRoutes mounts the routes of the RouteRegistrar registered as registrarName
+// under prefix when Container.MountRoutes runs. The registrar is only resolved
+// then, so a module can declare its routes while its controller lives elsewhere.
+func (mb *ModuleBuilder) AddRoutes(prefix, registrarName string) *ModuleBuilder {
+	return mb.AddToGroup(RoutesGroup, func(c *Container) any {
+		return &RouteMount{Prefix: prefix, Registrar: MustResolve[RouteRegistrar](c, registrarName)}
+	})
+}
+
+// MountRoutes resolves every member of RoutesGroup, adds its routes to a new
+// RouteTable under prefix and mounts them on router. Members are RouteMounts, as
+// added by AddRoutes, or plain RouteRegistrars mounted directly under prefix. The
+// table is returned so it can be listed, e.g. with IndexHandler.
+func (c *Container) MountRoutes(router fiber.Router, prefix string) (*RouteTable, error) {
+	table := NewRouteTable()
+	for i, member := range c.GetGroup(RoutesGroup) {
+		switch m := member.(type) {
+		case *RouteMount:
+			table.Register(joinRoutePath(prefix, m.Prefix), m.Registrar)
+		case RouteRegistrar:
+			table.Register(prefix, m)
+		default:
+			return nil, fmt.Errorf("member %d of group '%s' has type %T, not a RouteRegistrar", i, RoutesGroup, member)
+		}
+	}
+	table.Mount(router)
+	return table, nil
+}
@@ -0,0 +1,89 @@
+package xcomp
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeRegistrar struct {
+	routes []Route
+}
+
+func (r *fakeRegistrar) Routes() []Route {
+	return r.routes
+}
+
+func okHandler(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func TestMountRoutesRegistersGroupMembers(t *testing.T) {
+	built := 0
+	module := NewModule().
+		AddFactory("WidgetController", func(c *Container) any {
+			built++
+			return &fakeRegistrar{routes: []Route{
+				{Method: "get", Path: "/", Handler: okHandler},
+				{Method: fiber.MethodPost, Path: "/:id", Handler: okHandler},
+			}}
+		}).
+		AddRoutes("/widgets", "WidgetController").
+		AddToGroup(RoutesGroup, func(c *Container) any {
+			return &fakeRegistrar{routes: []Route{{Method: fiber.MethodGet, Path: "/health", Handler: okHandler}}}
+		}).
+		Build()
+
+	c := NewContainer()
+	if err := c.RegisterModule(module); err != nil {
+		t.Fatal(err)
+	}
+	if built != 0 {
+		t.Fatal("the registrar was built before MountRoutes")
+	}
+
+	app := fiber.New()
+	table, err := c.MountRoutes(app, "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, route := range table.Routes() {
+		got = append(got, route.Method+" "+route.Path)
+	}
+	want := []string{"GET /api/v1/widgets", "POST /api/v1/widgets/:id", "GET /api/v1/health"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mounted routes = %q, want %q", got, want)
+	}
+
+	for _, route := range table.Routes() {
+		path := route.Path
+		if route.Method == fiber.MethodPost {
+			path = "/api/v1/widgets/1"
+		}
+		resp, err := app.Test(httptest.NewRequest(route.Method, path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Errorf("%s %s = %d, want the mounted handler's 200", route.Method, path, resp.StatusCode)
+		}
+	}
+}
+
+func TestMountRoutesRejectsOtherGroupMembers(t *testing.T) {
+	module := NewModule().
+		AddToGroup(RoutesGroup, func(c *Container) any { return "not a registrar" }).
+		Build()
+	c := NewContainer()
+	if err := c.RegisterModule(module); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.MountRoutes(fiber.New(), "/api"); err == nil {
+		t.Fatal("MountRoutes accepted a group member that is not a RouteRegistrar")
+	}
+}
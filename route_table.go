@@ -0,0 +1,100 @@
+package xcomp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Route declares one endpoint so the API surface can be listed as well as mounted
+type Route struct {
+	Method  string        `json:"method"`
+	Path    string        `json:"path"`
+	Name    string        `json:"name,omitempty"`
+	Handler fiber.Handler `json:"-"`
+	// Middleware runs before Handler for this route only, e.g. ValidateBody
+	Middleware []fiber.Handler `json:"-"`
+}
+
+// RouteRegistrar is implemented by controllers that describe their routes declaratively
+type RouteRegistrar interface {
+	Routes() []Route
+}
+
+// RouteTable collects routes from registrars, mounts them on Fiber and lists them
+type RouteTable struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+func NewRouteTable() *RouteTable {
+	return &RouteTable{}
+}
+
+// Register adds the registrar's routes with prefix prepended to each path
+func (t *RouteTable) Register(prefix string, registrar RouteRegistrar) {
+	t.Add(prefix, registrar.Routes()...)
+}
+
+func (t *RouteTable) Add(prefix string, routes ...Route) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, route := range routes {
+		route.Method = strings.ToUpper(route.Method)
+		route.Path = joinRoutePath(prefix, route.Path)
+		t.routes = append(t.routes, route)
+	}
+}
+
+// Mount registers every route on router in the order they were added
+func (t *RouteTable) Mount(router fiber.Router) {
+	for _, route := range t.Routes() {
+		handlers := append(append([]fiber.Handler(nil), route.Middleware...), route.Handler)
+		mounted := router.Add(route.Method, route.Path, handlers...)
+		if route.Name != "" {
+			mounted.Name(route.Name)
+		}
+	}
+}
+
+// Routes returns a copy of the table in registration order
+func (t *RouteTable) Routes() []Route {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]Route(nil), t.routes...)
+}
+
+// IndexHandler serves the table as JSON sorted by path then method, e.g. for GET /routes
+func (t *RouteTable) IndexHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		routes := t.Routes()
+		sort.SliceStable(routes, func(i, j int) bool {
+			if routes[i].Path != routes[j].Path {
+				return routes[i].Path < routes[j].Path
+			}
+			return routes[i].Method < routes[j].Method
+		})
+
+		return c.JSON(fiber.Map{
+			"routes": routes,
+			"total":  len(routes),
+		})
+	}
+}
+
+func joinRoutePath(prefix, path string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if path == "" || path == "/" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
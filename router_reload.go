@@ -0,0 +1,84 @@
+package xcomp
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// ReloadableRouter serves requests from a Fiber app that can be rebuilt at runtime.
+// Reload builds a fresh app and swaps it in atomically: requests already running
+// finish on the old handler set while new requests go to the new one.
+// It is meant for development; Go code changes still need a restart, but route
+// config (prefixes, enabled modules) can be re-read without one.
+type ReloadableRouter struct {
+	build   func() (*fiber.App, error)
+	current atomic.Pointer[fasthttp.RequestHandler]
+	logger  Logger
+}
+
+func NewReloadableRouter(build func() (*fiber.App, error), logger Logger) (*ReloadableRouter, error) {
+	router := &ReloadableRouter{build: build, logger: logger}
+	if err := router.Reload(); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+func (r *ReloadableRouter) Reload() error {
+	app, err := r.build()
+	if err != nil {
+		return err
+	}
+
+	handler := app.Handler()
+	r.current.Store(&handler)
+	return nil
+}
+
+// Handler mounts the router into a parent app, e.g. app.Use(router.Handler())
+func (r *ReloadableRouter) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		handler := *r.current.Load()
+		handler(c.Context())
+		return nil
+	}
+}
+
+// ReloadOnSignal rebuilds the router every time one of signals arrives (SIGHUP by default)
+// until ctx is done. A failed rebuild keeps the previous handler set. When routes are
+// built from a ConfigService that also reloads on the signal, call Reload from its
+// OnReload hook instead, so the rebuild sees the new config rather than racing it.
+func (r *ReloadableRouter) ReloadOnSignal(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, signals...)
+
+	go func() {
+		defer signal.Stop(reload)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				if err := r.Reload(); err != nil {
+					if r.logger != nil {
+						r.logger.Error("Route reload failed, keeping previous routes", Err(err))
+					}
+					continue
+				}
+				if r.logger != nil {
+					r.logger.Info("Routes reloaded")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package xcomp
+
+import "context"
+
+// NewScope returns a child container for request-scoped services, such as the
+// authenticated user or a per-request transaction. Names not registered in the
+// scope resolve from the parent, so its singletons are shared, while services
+// registered in the scope stay invisible to the parent and to sibling scopes.
+// Singletons registered in a scope are built once per scope, and parent singletons
+// are always built against the parent, never against a scope.
+//
+// Close the scope when the request ends to release what it built.
+func (c *Container) NewScope() *Container {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.newScopeLocked()
+}
+
+// newScopeLocked is NewScope for callers holding the lock
+func (c *Container) newScopeLocked() *Container {
+	scope := &Container{
+		services:     make(map[string]any),
+		parent:       c,
+		moduleFilter: c.moduleFilter,
+		strictInject: c.strictInject,
+	}
+	scope.resolution.setLimit(c.resolution.limit())
+	return scope
+}
+
+// Parent is the container the scope was created from, or nil for a root container
+func (c *Container) Parent() *Container {
+	return c.parent
+}
+
+// Close closes the singletons built by this container only, see Shutdown; for a
+// scope that leaves the parent's services untouched
+func (c *Container) Close() error {
+	return c.Shutdown(context.Background())
+}
+
+// lookup finds the registration for name in this container or its ancestors
+func (c *Container) lookup(name string) (any, bool) {
+	for scope := c; scope != nil; scope = scope.parent {
+		scope.mutex.RLock()
+		service, ok := scope.services[name]
+		scope.mutex.RUnlock()
+		if ok {
+			return service, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,141 @@
+package xcomp
+
+import (
+	"testing"
+	"time"
+)
+
+type benchOrder struct {
+	ID         string          `json:"id"`
+	CustomerID string          `json:"customer_id"`
+	Status     string          `json:"status"`
+	Total      float64         `json:"total"`
+	Notes      string          `json:"notes"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Items      []benchLineItem `json:"items"`
+}
+
+type benchLineItem struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+func newBenchOrder() benchOrder {
+	order := benchOrder{
+		ID:         "2b7c6a8e-6f1e-4d1f-9a55-8f2f7a0f3c11",
+		CustomerID: "5d0c9f1e-0b7a-4c47-8d3e-1f6f2f1d9b20",
+		Status:     "pending",
+		Total:      249.95,
+		Notes:      "leave at the front desk",
+		CreatedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	for i := 0; i < 10; i++ {
+		order.Items = append(order.Items, benchLineItem{
+			ProductID: "a1f0c2d4-1111-2222-3333-444455556666",
+			Name:      "Mechanical keyboard",
+			Quantity:  i + 1,
+			UnitPrice: 24.99,
+		})
+	}
+	return order
+}
+
+func TestPooledJSONSerializerMatchesJSONSerializer(t *testing.T) {
+	pooled := NewPooledJSONSerializer()
+	order := newBenchOrder()
+
+	want, err := JSONSerializer{}.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Twice, so the second call reuses a pooled buffer
+	for i := 0; i < 2; i++ {
+		got, err := pooled.Marshal(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("pooled Marshal = %s, want %s", got, want)
+		}
+	}
+
+	var decoded benchOrder
+	if err := pooled.Unmarshal(want, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID != order.ID || len(decoded.Items) != len(order.Items) {
+		t.Fatalf("Unmarshal = %+v, want %+v", decoded, order)
+	}
+}
+
+func TestPooledJSONSerializerResultIsNotReused(t *testing.T) {
+	pooled := NewPooledJSONSerializer()
+
+	first, err := pooled.Marshal(map[string]string{"a": "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pooled.Marshal(map[string]string{"a": "second"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != `{"a":"first"}` {
+		t.Fatalf("first result changed to %s after the buffer was reused", first)
+	}
+}
+
+func benchmarkMarshal(b *testing.B, serializer Serializer) {
+	order := newBenchOrder()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := serializer.Marshal(order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkMarshalParallel(b *testing.B, serializer Serializer) {
+	order := newBenchOrder()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := serializer.Marshal(order); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func benchmarkUnmarshal(b *testing.B, serializer Serializer) {
+	data, err := JSONSerializer{}.Marshal(newBenchOrder())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var order benchOrder
+		if err := serializer.Unmarshal(data, &order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSerializer compares the pooled serializer against the plain one;
+// run with -bench Serializer -benchmem
+func BenchmarkSerializer(b *testing.B) {
+	serializers := []struct {
+		name       string
+		serializer Serializer
+	}{
+		{"JSON", JSONSerializer{}},
+		{"PooledJSON", NewPooledJSONSerializer()},
+	}
+	for _, s := range serializers {
+		b.Run(s.name+"/Marshal", func(b *testing.B) { benchmarkMarshal(b, s.serializer) })
+		b.Run(s.name+"/MarshalParallel", func(b *testing.B) { benchmarkMarshalParallel(b, s.serializer) })
+		b.Run(s.name+"/Unmarshal", func(b *testing.B) { benchmarkUnmarshal(b, s.serializer) })
+	}
+}
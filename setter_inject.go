@@ -0,0 +1,65 @@
+package xcomp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SetterInjectable lets a struct keep its dependencies in unexported fields: Inject
+// calls each listed setter with the named service. Keys are setter method names,
+// values service names, optionally suffixed with ",optional" as in inject tags:
+//
+//	func (s *OrderService) InjectSetters() map[string]string {
+//		return map[string]string{"SetOrderRepository": "OrderRepository"}
+//	}
+type SetterInjectable interface {
+	InjectSetters() map[string]string
+}
+
+func (c *Container) injectSetters(target SetterInjectable) error {
+	setters := target.InjectSetters()
+	methods := make([]string, 0, len(setters))
+	for method := range setters {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	targetValue := reflect.ValueOf(target)
+	for _, methodName := range methods {
+		serviceName, optional := parseInjectTag(setters[methodName])
+
+		method := targetValue.MethodByName(methodName)
+		if !method.IsValid() {
+			return fmt.Errorf("setter '%s' for service '%s' not found on %T", methodName, serviceName, target)
+		}
+		if method.Type().NumIn() != 1 {
+			return fmt.Errorf("setter '%s' on %T must take exactly one argument", methodName, target)
+		}
+
+		if group, ok := parseGroupTag(serviceName); ok {
+			members, err := c.groupValue(group, method.Type().In(0))
+			if err != nil {
+				return fmt.Errorf("setter '%s': %w", methodName, err)
+			}
+			method.Call([]reflect.Value{members})
+			continue
+		}
+
+		service := c.Get(serviceName)
+		if service == nil {
+			if optional {
+				continue
+			}
+			return fmt.Errorf("service '%s' not found for setter '%s'", serviceName, methodName)
+		}
+
+		serviceValue := reflect.ValueOf(service)
+		if !serviceValue.Type().AssignableTo(method.Type().In(0)) {
+			return fmt.Errorf("service '%s' is not assignable to the argument of setter '%s'", serviceName, methodName)
+		}
+
+		method.Call([]reflect.Value{serviceValue})
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package xcomp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds each step of ShutdownManager.Shutdown unless
+// the step was added with its own timeout
+const DefaultShutdownTimeout = 10 * time.Second
+
+// ShutdownManager coordinates teardown: steps run in reverse order of Add, so
+// whatever started last stops first, each bounded by its own timeout
+type ShutdownManager struct {
+	logger  Logger
+	timeout time.Duration
+
+	mu    sync.Mutex
+	steps []shutdownStep
+	done  bool
+}
+
+type shutdownStep struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+	// container steps expand into one step per disposable when Shutdown runs,
+	// so services built after AddContainer are closed too
+	container *Container
+}
+
+// NewShutdownManager logs each step with logger, which may be nil; a
+// non-positive timeout means DefaultShutdownTimeout
+func NewShutdownManager(logger Logger, timeout time.Duration) *ShutdownManager {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	return &ShutdownManager{logger: logger, timeout: timeout}
+}
+
+// Add registers fn to run on Shutdown with the default timeout
+func (m *ShutdownManager) Add(name string, fn func(ctx context.Context) error) {
+	m.AddWithTimeout(name, m.timeout, fn)
+}
+
+// AddWithTimeout registers fn to run on Shutdown, given at most timeout
+func (m *ShutdownManager) AddWithTimeout(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	m.add(shutdownStep{name: name, timeout: timeout, fn: fn})
+}
+
+// AddContainer closes the services c has built when Shutdown reaches it,
+// each as its own step, in the order Container.Shutdown would
+func (m *ShutdownManager) AddContainer(c *Container) {
+	m.add(shutdownStep{container: c})
+}
+
+func (m *ShutdownManager) add(step shutdownStep) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.steps = append(m.steps, step)
+}
+
+// Shutdown runs the registered steps in LIFO order and returns their errors
+// joined. A step that outlives its timeout is abandoned and reported as
+// failed; the remaining steps still run. Later calls do nothing.
+func (m *ShutdownManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.done {
+		m.mu.Unlock()
+		return nil
+	}
+	m.done = true
+	steps := m.steps
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if step.container == nil {
+			errs = append(errs, m.run(ctx, step.name, step.timeout, step.fn))
+			continue
+		}
+		for _, closer := range step.container.takeClosers() {
+			errs = append(errs, m.run(ctx, closer.name, m.timeout, closer.close))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *ShutdownManager) run(ctx context.Context, name string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		result <- fn(stepCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-stepCtx.Done():
+		err = stepCtx.Err()
+	}
+
+	duration := time.Since(start)
+	if err != nil {
+		err = fmt.Errorf("shutdown of '%s' failed: %w", name, err)
+		if m.logger != nil {
+			m.logger.Error("Shutdown step failed",
+				Field("step", name),
+				Duration("duration", duration),
+				Err(err))
+		}
+		return err
+	}
+
+	if m.logger != nil {
+		m.logger.Info("Shutdown step completed",
+			Field("step", name),
+			Duration("duration", duration))
+	}
+	return nil
+}
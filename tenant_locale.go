@@ -0,0 +1,159 @@
+package xcomp
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+type tenantKey struct{}
+
+type localeKey struct{}
+
+// tenantIDPattern keeps tenant IDs safe to embed in cache keys
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant stored by the tenant middleware, or "" for
+// requests without one
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale negotiated by the locale middleware, or ""
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// TenantCacheKey prefixes key with ctx's tenant, so tenants sharing a cache never
+// read each other's entries. Keys of requests without a tenant are unchanged.
+func TenantCacheKey(ctx context.Context, key string) string {
+	if tenantID := TenantFromContext(ctx); tenantID != "" {
+		return "tenant:" + tenantID + ":" + key
+	}
+	return key
+}
+
+// NewTenantMiddleware reads the tenant ID from header (X-Tenant-ID when empty) into
+// the user context. The header is trusted as is, so it must be set or checked by a
+// gateway in front of the service. Requests without it have no tenant; malformed
+// IDs are rejected.
+func NewTenantMiddleware(header string) fiber.Handler {
+	if header == "" {
+		header = TenantHeader
+	}
+	return func(c *fiber.Ctx) error {
+		tenantID := c.Get(header)
+		if tenantID == "" {
+			return c.Next()
+		}
+		if !tenantIDPattern.MatchString(tenantID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid tenant",
+				"message": header + " must be 1-64 letters, digits, '-' or '_'",
+			})
+		}
+
+		c.Locals("tenant_id", tenantID)
+		c.SetUserContext(ContextWithTenant(c.UserContext(), tenantID))
+		return c.Next()
+	}
+}
+
+// NewLocaleMiddleware picks the best of the supported locales for the request's
+// Accept-Language header and stores it in the user context. A language matches a
+// supported locale exactly or by its base ("en-GB" matches "en"); without a match
+// the first supported locale is used.
+func NewLocaleMiddleware(supported ...string) fiber.Handler {
+	if len(supported) == 0 {
+		supported = []string{"en"}
+	}
+	return func(c *fiber.Ctx) error {
+		locale := NegotiateLocale(c.Get(fiber.HeaderAcceptLanguage), supported)
+		c.Locals("locale", locale)
+		c.SetUserContext(ContextWithLocale(c.UserContext(), locale))
+		return c.Next()
+	}
+}
+
+// NegotiateLocale returns the supported locale best matching an Accept-Language
+// header, honouring q-values, or supported[0] when none matches
+func NegotiateLocale(acceptLanguage string, supported []string) string {
+	for _, language := range parseAcceptLanguage(acceptLanguage) {
+		for _, locale := range supported {
+			if strings.EqualFold(language, locale) {
+				return locale
+			}
+		}
+		base, _, _ := strings.Cut(language, "-")
+		for _, locale := range supported {
+			if strings.EqualFold(base, locale) {
+				return locale
+			}
+		}
+	}
+	if len(supported) == 0 {
+		return ""
+	}
+	return supported[0]
+}
+
+// parseAcceptLanguage returns the header's language tags by descending q-value
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			languages = append(languages, weighted{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
@@ -0,0 +1,266 @@
+package xcomp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validatable is implemented by request types with rules struct tags can't express,
+// such as constraints between fields. Validate runs after tag validation passes.
+type Validatable interface {
+	Validate() error
+}
+
+// Validator runs validate struct tags and Validatable rules, see Validate. It is
+// registered as "Validator" by NewValidatorModule for services that validate
+// input outside of HTTP handlers.
+type Validator struct {
+	validate *validator.Validate
+}
+
+func NewValidator() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	// Field errors name fields as clients send them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return &Validator{validate: validate}
+}
+
+func (v *Validator) GetServiceName() string {
+	return "Validator"
+}
+
+// Validate checks target's validate struct tags and then, if target implements
+// Validatable, its own cross-field rules. Tag failures are validator.ValidationErrors,
+// see FieldErrors.
+func (v *Validator) Validate(target any) error {
+	value := reflect.ValueOf(target)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct {
+		if err := v.validate.Struct(target); err != nil {
+			return err
+		}
+	}
+
+	if validatable, ok := target.(Validatable); ok {
+		return validatable.Validate()
+	}
+	return nil
+}
+
+// NewValidatorModule provides a Validator as "Validator"
+func NewValidatorModule() Module {
+	return NewModule().
+		AddService("Validator", defaultValidator).
+		Build()
+}
+
+var defaultValidator = NewValidator()
+
+// Validate is Validator.Validate with the shared default validator
+func Validate(v any) error {
+	return defaultValidator.Validate(v)
+}
+
+// FieldError is one failed validate tag, as reported to clients
+type FieldError struct {
+	// Field is the path of the field by its JSON names, e.g. "items[0].quantity"
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// FieldErrors lists the tag failures in err, or nil when err does not come from
+// validate tags, e.g. a Validatable rule
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// The namespace starts with the struct type, which clients never see
+		field := fieldErr.Namespace()
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest
+		}
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldErrorMessage(fieldErr),
+		})
+	}
+	return fields
+}
+
+func fieldErrorMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "min", "gte":
+		if unit := lengthUnit(fieldErr.Kind()); unit != "" {
+			return fmt.Sprintf("must have at least %s %s", fieldErr.Param(), unit)
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max", "lte":
+		if unit := lengthUnit(fieldErr.Kind()); unit != "" {
+			return fmt.Sprintf("must have at most %s %s", fieldErr.Param(), unit)
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldErr.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fieldErr.Param())
+	}
+	return fmt.Sprintf("failed the '%s' rule", fieldErr.Tag())
+}
+
+// lengthUnit is what min and max count for kinds they measure by length
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return ""
+}
+
+// BodyParseError is returned by BindAndValidate for bodies that can't be parsed
+type BodyParseError struct {
+	Err error
+}
+
+func (e *BodyParseError) Error() string {
+	return "invalid request body: " + e.Err.Error()
+}
+
+func (e *BodyParseError) Unwrap() error {
+	return e.Err
+}
+
+// BindAndValidate parses the request body into out and validates it with Validate.
+// Answer a failure with WriteBindError:
+//
+//	var req dto.CreateProductRequest
+//	if err := xcomp.BindAndValidate(c, &req); err != nil {
+//		return xcomp.WriteBindError(c, err)
+//	}
+func BindAndValidate(c *fiber.Ctx, out any) error {
+	if err := c.BodyParser(out); err != nil {
+		return &BodyParseError{Err: err}
+	}
+	return Validate(out)
+}
+
+// WriteBindError answers a BindAndValidate failure: 400 for an unparsable body,
+// 422 for an invalid one, listing the failed fields when they come from tags
+func WriteBindError(c *fiber.Ctx, err error) error {
+	var parseErr *BodyParseError
+	if errors.As(err, &parseErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": parseErr.Err.Error(),
+		})
+	}
+
+	body := fiber.Map{
+		"error":   "Validation failed",
+		"message": err.Error(),
+	}
+	if fields := FieldErrors(err); fields != nil {
+		body["message"] = "one or more fields are invalid"
+		body["fields"] = fields
+	}
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(body)
+}
+
+const validatedBodyKey = "xcomp.validated_body"
+
+// ValidateBody parses the request body into a T, validates it with Validate and
+// stores it for ValidatedBody. Invalid requests are answered with 400 or 422.
+func ValidateBody[T any]() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body := new(T)
+		if err := BindAndValidate(c, body); err != nil {
+			return WriteBindError(c, err)
+		}
+
+		c.Locals(validatedBodyKey, body)
+		return c.Next()
+	}
+}
+
+// ValidatedBody returns the body stored by ValidateBody, or nil if it did not run for T
+func ValidatedBody[T any](c *fiber.Ctx) *T {
+	body, _ := c.Locals(validatedBodyKey).(*T)
+	return body
+}
+
+// EntityMapper is implemented by create requests that build their domain entity
+type EntityMapper[E any] interface {
+	ToEntity() E
+}
+
+// EntityUpdater is implemented by update requests that copy their fields onto an
+// existing entity
+type EntityUpdater[E any] interface {
+	ApplyTo(entity E)
+}
+
+// ToEntity validates the request with Validate, builds the entity and then runs the
+// entity's own Validate if it has one, so no entity is built from an invalid request
+func ToEntity[E any](request EntityMapper[E]) (E, error) {
+	var zero E
+	if err := Validate(request); err != nil {
+		return zero, err
+	}
+
+	entity := request.ToEntity()
+	if err := validateEntity(entity); err != nil {
+		return zero, err
+	}
+	return entity, nil
+}
+
+// ApplyTo validates the request, applies it to entity and validates the result.
+// On a request validation error entity is left untouched.
+func ApplyTo[E any](request EntityUpdater[E], entity E) error {
+	if err := Validate(request); err != nil {
+		return err
+	}
+
+	request.ApplyTo(entity)
+	return validateEntity(entity)
+}
+
+func validateEntity(entity any) error {
+	if validatable, ok := entity.(Validatable); ok {
+		return validatable.Validate()
+	}
+	return nil
+}
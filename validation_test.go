@@ -0,0 +1,91 @@
+package xcomp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type signupRequest struct {
+	Username string `json:"username" validate:"required,min=3"`
+	Email    string `json:"email" validate:"required,email"`
+	Age      int    `json:"age" validate:"gte=0"`
+}
+
+func TestValidateReportsFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		request signupRequest
+		field   string
+		rule    string
+		message string
+	}{
+		{
+			name:    "required",
+			request: signupRequest{Email: "ada@example.com"},
+			field:   "username", rule: "required", message: "is required",
+		},
+		{
+			name:    "min",
+			request: signupRequest{Username: "ad", Email: "ada@example.com"},
+			field:   "username", rule: "min", message: "must have at least 3 characters",
+		},
+		{
+			name:    "email",
+			request: signupRequest{Username: "ada", Email: "not-an-email"},
+			field:   "email", rule: "email", message: "must be a valid email address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := FieldErrors(Validate(&tt.request))
+			if len(fields) != 1 {
+				t.Fatalf("FieldErrors = %+v, want one failure", fields)
+			}
+			got := fields[0]
+			if got.Field != tt.field || got.Rule != tt.rule || got.Message != tt.message {
+				t.Fatalf("FieldErrors = %+v, want %s failing %s with %q", got, tt.field, tt.rule, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsZeroWithoutRequired(t *testing.T) {
+	if err := Validate(&signupRequest{Username: "ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("Validate = %v, want a zero age to pass gte=0", err)
+	}
+}
+
+func TestValidateBodyAnswersWithFieldErrors(t *testing.T) {
+	app := fiber.New()
+	app.Post("/signup", ValidateBody[signupRequest](), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/signup", strings.NewReader(`{"username":"ad","email":"nope"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusUnprocessableEntity)
+	}
+
+	var body struct {
+		Fields []FieldError `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	failed := map[string]string{}
+	for _, field := range body.Fields {
+		failed[field.Field] = field.Rule
+	}
+	if len(failed) != 2 || failed["username"] != "min" || failed["email"] != "email" {
+		t.Fatalf("fields = %+v, want username failing min and email failing email", body.Fields)
+	}
+}